@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamcanCmd represents the iam can command
+var iamcanCmd = &cobra.Command{
+	Use:   "can",
+	Short: "Check if a role or user is allowed to perform an action",
+	Long: `Evaluates locally whether a role or user is allowed to perform an action on a
+resource, and shows which policy statements decided the result.
+
+The identity policies of the principal are collected, including inline and attached
+policies and, for users, the policies inherited from their groups. These are then
+evaluated the same way IAM does within a single account:
+
+  - an explicit Deny in any policy, including the permissions boundary, always wins
+  - otherwise an Allow in at least one identity policy is required
+  - if a permissions boundary is set, it needs to allow the action as well
+
+Wildcards in actions and resources as well as NotAction and NotResource are
+supported. Conditions are not evaluated; statements with conditions are treated as
+if the conditions are met and the result is marked as conditional. Resource-based
+policies, SCPs, and session policies are not taken into account.
+
+The principal can be provided as a name, an ARN, or in the form role/name or
+user/name when a role and user share the same name.
+
+Examples:
+
+	awstools iam can --principal MyRole --action s3:GetObject --resource arn:aws:s3:::my-bucket/file.txt
+	awstools iam can --principal user/alice --action ec2:TerminateInstances --resource "*" -o table`,
+	Run: iamcan,
+}
+
+var iamcanPrincipal string
+var iamcanAction string
+var iamcanResource string
+
+func init() {
+	iamCmd.AddCommand(iamcanCmd)
+	iamcanCmd.Flags().StringVar(&iamcanPrincipal, "principal", "", "The name or ARN of the role or user")
+	iamcanCmd.Flags().StringVar(&iamcanAction, "action", "", "The action to evaluate, e.g. s3:GetObject")
+	iamcanCmd.Flags().StringVar(&iamcanResource, "resource", "", "The ARN of the resource to evaluate the action against")
+	_ = iamcanCmd.MarkFlagRequired("principal")
+	_ = iamcanCmd.MarkFlagRequired("action")
+	_ = iamcanCmd.MarkFlagRequired("resource")
+}
+
+func iamcan(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	principal, err := helpers.GetIAMPrincipalPolicies(iamcanPrincipal, awsConfig.IamClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	result := helpers.EvaluateIAMPolicies(principal, iamcanAction, iamcanResource)
+	resultTitle := fmt.Sprintf("Can %s %s perform %s on %s", principal.Type, principal.Name, iamcanAction, iamcanResource)
+	keys := []string{"Principal", "Action", "Resource", "Decision", "Reason", "Decided By"}
+	if settings.IsVerbose() {
+		keys = append(keys, "Policies Evaluated")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	content := make(map[string]any)
+	content["Principal"] = fmt.Sprintf("%s (%s)", principal.Name, principal.Type)
+	content["Action"] = result.Action
+	content["Resource"] = result.Resource
+	content["Decision"] = iamDecision(result, output.Settings.UseEmoji)
+	reason := result.Reason
+	if result.IsConditional() {
+		reason += " (depends on unevaluated conditions)"
+	}
+	content["Reason"] = reason
+	decidedBy := make([]string, 0, len(result.DecidedBy))
+	for _, match := range result.DecidedBy {
+		decidedBy = append(decidedBy, match.String())
+	}
+	content["Decided By"] = decidedBy
+	if settings.IsVerbose() {
+		policies := make([]string, 0, len(principal.Policies)+1)
+		for _, policy := range principal.Policies {
+			policies = append(policies, fmt.Sprintf("%s (%s)", policy.Name, policy.Type))
+		}
+		if principal.PermissionsBoundary != nil {
+			policies = append(policies, fmt.Sprintf("%s (%s)", principal.PermissionsBoundary.Name, principal.PermissionsBoundary.Type))
+		}
+		content["Policies Evaluated"] = policies
+	}
+	output.AddContents(content)
+	output.Write()
+}
+
+func iamDecision(result helpers.IAMEvaluationResult, useEmoji bool) string {
+	switch {
+	case result.IsAllowed() && result.IsConditional():
+		return emojiPrefix("⚠️ ", result.Decision, useEmoji)
+	case result.IsAllowed():
+		return emojiPrefix("✅ ", result.Decision, useEmoji)
+	}
+	return emojiPrefix("❌ ", result.Decision, useEmoji)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamcompareCmd represents the iam compare command
+var iamcompareCmd = &cobra.Command{
+	Use:   "compare <principalA> <principalB>",
+	Short: "Compare the policies of two roles, users, or permission sets",
+	Long: `Compares the identity policies of two principals and shows the grants that only
+one of them has. Every statement is split into a grant per effect, action, and
+resource, so the comparison doesn't depend on how the policies are structured.
+
+Principals can be roles or users (by name, ARN, or prefixed with role/ or user/)
+and SSO permission sets (by ARN, or by name prefixed with permission-set/). The
+policies of users include the ones inherited from their groups. The customer
+managed policies of permission sets are read from the current account, and the
+comparison fails if they don't exist there. Permissions boundaries aren't
+compared.
+
+Grants are compared literally: s3:Get* and s3:GetObject are shown as different
+grants even though one includes the other. Use "iam can" to check a specific
+action.
+
+Example:
+
+	awstools iam compare dev-deploy prod-deploy -o table
+	awstools iam compare role/dev-deploy permission-set/Developer -o table`,
+	Args: cobra.ExactArgs(2),
+	Run:  iamcompare,
+}
+
+func init() {
+	iamCmd.AddCommand(iamcompareCmd)
+}
+
+func iamcompare(_ *cobra.Command, args []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	first, err := helpers.GetComparablePrincipalPolicies(args[0], awsConfig.AccountID, awsConfig.IamClient(), awsConfig.SsoClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	second, err := helpers.GetComparablePrincipalPolicies(args[1], awsConfig.AccountID, awsConfig.IamClient(), awsConfig.SsoClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	comparison := helpers.CompareIAMPrincipals(first, second)
+	keys := []string{"Only In", "Effect", "Action", "Resource", "Condition", "Statements"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = fmt.Sprintf("Policy differences between %s %s and %s %s (%d grants in common)",
+		first.Type, first.Name, second.Type, second.Name, comparison.SharedCount)
+	addComparedGrants(&output, first.Name, comparison.OnlyInFirst)
+	addComparedGrants(&output, second.Name, comparison.OnlyInSecond)
+	output.Write()
+}
+
+func addComparedGrants(output *format.OutputArray, principal string, grants []helpers.IAMGrant) {
+	for _, grant := range grants {
+		content := make(map[string]any)
+		content["Only In"] = principal
+		content["Effect"] = grant.Effect
+		content["Action"] = grant.Action
+		content["Resource"] = grant.Resource
+		content["Condition"] = grant.Condition
+		statements := make([]string, 0, len(grant.Sources))
+		for _, statement := range grant.Sources {
+			statements = append(statements, statement.String())
+		}
+		content["Statements"] = statements
+		output.AddContents(content)
+	}
+}
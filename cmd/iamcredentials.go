@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamcredentialsCmd represents the iam credentials command
+var iamcredentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Show MFA, console access, and access key hygiene for all users",
+	Long: `Generates and parses the IAM credential report to show for every user whether
+MFA is enabled, whether they have console access, and the age, last rotation,
+and last use of their access keys.
+
+The following are flagged:
+
+  - console access without MFA
+  - active access keys that haven't been rotated within --max-key-age days
+  - root account usage, active root access keys, and a root account without MFA
+
+As the credential report contains the details for all users, this requires only
+a couple of API calls regardless of the number of users in the account.
+
+Example:
+
+	awstools iam credentials --max-key-age 180 -o table`,
+	Run: iamcredentials,
+}
+
+var iamcredentialsMaxKeyAge int
+
+func init() {
+	iamCmd.AddCommand(iamcredentialsCmd)
+	iamcredentialsCmd.Flags().IntVar(&iamcredentialsMaxKeyAge, "max-key-age", 90, "The maximum age in days of an active access key before it is flagged")
+}
+
+func iamcredentials(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	resultTitle := "IAM credential report for account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	entries := helpers.GetCredentialReport(awsConfig.IamClient())
+	keys := []string{"User", "MFA", "Console Access", "Password Last Used", "Access Keys", "Last Used", "Findings"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	now := time.Now()
+	for _, entry := range entries {
+		content := make(map[string]any)
+		content["User"] = entry.User
+		content["MFA"] = entry.MFAActive
+		content["Console Access"] = entry.PasswordEnabled
+		content["Password Last Used"] = credentialTime(entry.PasswordLastUsed)
+		accesskeys := []string{}
+		for _, key := range entry.ActiveAccessKeys() {
+			accesskeys = append(accesskeys, accessKeySummary(key, now))
+		}
+		content["Access Keys"] = accesskeys
+		content["Last Used"] = credentialTime(entry.LastUsed())
+		findings := entry.Findings(iamcredentialsMaxKeyAge, now)
+		if output.Settings.UseEmoji {
+			for i, finding := range findings {
+				findings[i] = "⚠️ " + finding
+			}
+		}
+		content["Findings"] = findings
+		output.AddContents(content)
+	}
+	output.Write()
+}
+
+// accessKeySummary describes the age and last use of an access key
+func accessKeySummary(key helpers.IAMCredentialReportAccessKey, now time.Time) string {
+	summary := fmt.Sprintf("Key %d: %d days old (rotated %s)", key.Number, key.AgeInDays(now), credentialTime(key.LastRotated))
+	if key.LastUsed.IsZero() {
+		return summary + ", never used"
+	}
+	details := []string{}
+	for _, detail := range []string{key.LastUsedService, key.LastUsedRegion} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+	summary += ", last used " + credentialTime(key.LastUsed)
+	if len(details) > 0 {
+		summary += " (" + strings.Join(details, " in ") + ")"
+	}
+	return summary
+}
+
+func credentialTime(value time.Time) string {
+	if value.IsZero() {
+		return "Never"
+	}
+	return value.Format(time.DateOnly)
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/spf13/cobra"
+)
+
+// iameffectiveCmd represents the iam effective command
+var iameffectiveCmd = &cobra.Command{
+	Use:   "effective",
+	Short: "Check which actions a role can perform after SCPs and boundaries",
+	Long: `Evaluates a list of actions for a role by combining the three layers that decide
+whether a request is allowed:
+
+  - the Service Control Policies (SCPs) inherited from the root through each OU
+    down to the account
+  - the permissions boundary of the role
+  - the identity policies of the role
+
+For every action the result shows whether it is ultimately allowed and, if not,
+which layer denies it. An explicit Deny in any layer always wins, and otherwise
+every layer needs to allow the action. SCPs don't apply to the management
+account, so they are skipped for it.
+
+Conditions are not evaluated. Identity policy and boundary statements with
+conditions are treated as if the conditions are met. SCP Deny statements with
+conditions, such as region restrictions, don't deny the action but mark the
+result as conditional. Resource-based policies and session policies are not
+taken into account.
+
+The role is read with the current credentials, so the account that is evaluated
+is the account of the current profile. To check a role in another account, use
+--profile to select a profile for that account. The SCPs need to be read from
+the management account or a delegated administrator account, which you can do
+with a separate profile through --org-profile.
+
+Example:
+
+	awstools iam effective --role deploy --actions s3:PutObject,kms:Decrypt --org-profile management -o table`,
+	Run: iameffective,
+}
+
+var iameffectiveRole string
+var iameffectiveActions []string
+var iameffectiveResource string
+var iameffectiveOrgProfile string
+
+func init() {
+	iamCmd.AddCommand(iameffectiveCmd)
+	iameffectiveCmd.Flags().StringVar(&iameffectiveRole, "role", "", "The name or ARN of the role")
+	iameffectiveCmd.Flags().StringSliceVar(&iameffectiveActions, "actions", []string{}, "The actions to evaluate, e.g. s3:GetObject,s3:PutObject")
+	iameffectiveCmd.Flags().StringVar(&iameffectiveResource, "resource", "*", "The ARN of the resource to evaluate the actions against")
+	iameffectiveCmd.Flags().StringVar(&iameffectiveOrgProfile, "org-profile", "", "The profile used to read the SCPs (defaults to the current profile)")
+	_ = iameffectiveCmd.MarkFlagRequired("role")
+	_ = iameffectiveCmd.MarkFlagRequired("actions")
+}
+
+func iameffective(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	account := awsConfig.AccountID
+	principal, err := helpers.GetIAMPrincipalPolicies("role/"+iameffectiveRole, awsConfig.IamClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	orgConfig := awsConfig
+	if iameffectiveOrgProfile != "" {
+		orgConfig = config.AwsConfigForProfile(*settings, iameffectiveOrgProfile)
+	}
+	orgSvc := orgConfig.OrganizationsClient()
+	managementAccount, err := helpers.GetManagementAccountID(orgSvc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	resultTitle := fmt.Sprintf("Effective permissions of role %s in account %s", principal.Name, getName(account))
+	var levels []helpers.OrganizationPolicyLevel
+	if account == managementAccount {
+		resultTitle += " (management account, SCPs don't apply)"
+	} else {
+		policies, err := helpers.GetOrganizationPolicies(types.PolicyTypeServiceControlPolicy, orgSvc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		organization, err := helpers.GetFullOrganization(orgSvc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		levels, err = helpers.GetPolicyInheritance(organization, account, policies)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+	}
+	results, err := helpers.EvaluateEffectivePermissions(principal, levels, iameffectiveActions, iameffectiveResource)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"Action", "Resource", "Decision", "Denied By", "Reason", "Decided By"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	for _, result := range results {
+		content := make(map[string]any)
+		content["Action"] = result.Action
+		content["Resource"] = result.Resource
+		content["Decision"] = iamDecision(result.IAMEvaluationResult, output.Settings.UseEmoji)
+		content["Denied By"] = result.Layer
+		reason := result.Reason
+		if result.IsConditional() {
+			reason += " (depends on unevaluated conditions)"
+		}
+		content["Reason"] = reason
+		decidedBy := make([]string, 0, len(result.DecidedBy))
+		for _, match := range result.DecidedBy {
+			decidedBy = append(decidedBy, match.String())
+		}
+		content["Decided By"] = decidedBy
+		output.AddContents(content)
+	}
+	output.Write()
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamgrouplistCmd represents the iam grouplist command
+var iamgrouplistCmd = &cobra.Command{
+	Use:   "grouplist",
+	Short: "Get an overview of the IAM groups in the account",
+	Long: `Retrieves a list of all IAM groups in the account with their members and their
+inline and attached policies.
+
+Example:
+
+	awstools iam grouplist -o table`,
+	Run: iamgrouplist,
+}
+
+func init() {
+	iamCmd.AddCommand(iamgrouplistCmd)
+}
+
+func iamgrouplist(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	resultTitle := "IAM Group overview for account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	groups := helpers.GetGroupDetails(awsConfig.IamClient())
+	keys := []string{nameColumn, "Members", "Member Count", "Inline Policies", "Attached Policies"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	output.Settings.SortKey = nameColumn
+	for _, group := range groups {
+		content := make(map[string]any)
+		content[nameColumn] = group.Name
+		content["Members"] = group.Users
+		content["Member Count"] = len(group.Users)
+		content["Inline Policies"] = slices.Sorted(maps.Keys(group.InlinePolicies))
+		content["Attached Policies"] = slices.Sorted(maps.Keys(group.AttachedPolicies))
+		output.AddContents(content)
+	}
+	output.Write()
+}
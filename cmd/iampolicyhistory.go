@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iampolicyhistoryCmd represents the iam policy-history command
+var iampolicyhistoryCmd = &cobra.Command{
+	Use:   "policy-history [policy-arn]",
+	Short: "Show the versions of managed policies and what changed between them",
+	Long: `Lists every version of a managed policy with its creation date and the
+statements that were added or removed compared to the version before it. Without
+a policy ARN, the history of every customer managed policy in the account is
+shown.
+
+Statements are compared after sorting their actions and resources, so
+reordering a list isn't shown as a change. The oldest version has no changes,
+as earlier versions may have been deleted. Policies that are at the limit of 5
+versions are flagged, as a version has to be deleted before the policy can be
+changed again.
+
+Example:
+
+	awstools iam policy-history arn:aws:iam::123456789012:policy/deploy -o table
+	awstools iam policy-history -o table --emoji`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  iampolicyhistory,
+}
+
+func init() {
+	iamCmd.AddCommand(iampolicyhistoryCmd)
+}
+
+func iampolicyhistory(_ *cobra.Command, args []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.IamClient()
+	var histories []helpers.IAMPolicyHistory
+	var resultTitle string
+	if len(args) == 1 {
+		history, err := helpers.GetPolicyHistory(args[0], svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		histories = append(histories, history)
+		resultTitle = "Managed policy history for " + history.Name
+	} else {
+		var err error
+		histories, err = helpers.GetCustomerManagedPolicyHistories(svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		resultTitle = "Managed policy history for account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	}
+	keys := []string{"Policy", "Version", "Created", "Default", "Versions", "Added", "Removed"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	for _, history := range histories {
+		versions := fmt.Sprintf("%d/%d", len(history.Versions), helpers.IAMPolicyVersionLimit)
+		if history.AtVersionLimit() {
+			versions = emojiPrefix("⚠️ ", versions+" (at limit)", output.Settings.UseEmoji)
+		}
+		for _, version := range history.Versions {
+			content := make(map[string]any)
+			content["Policy"] = history.Name
+			content["Version"] = version.VersionID
+			content["Created"] = version.Created.Format(time.RFC3339)
+			content["Default"] = version.IsDefault
+			content["Versions"] = versions
+			var added, removed []string
+			for _, change := range version.Changes {
+				switch change.Change {
+				case helpers.IAMPolicyChangeAdded:
+					added = append(added, change.Statement)
+				case helpers.IAMPolicyChangeRemoved:
+					removed = append(removed, change.Statement)
+				}
+			}
+			content["Added"] = added
+			content["Removed"] = removed
+			output.AddContents(content)
+		}
+	}
+	output.Write()
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iampolicylistCmd represents the iam policylist command
+var iampolicylistCmd = &cobra.Command{
+	Use:   "policylist",
+	Short: "Get an overview of the customer managed IAM policies",
+	Long: `Retrieves a list of all customer managed IAM policies in the account with the
+number of principals they're attached to, the users, groups, and roles they're
+attached to, and their default version.
+
+Policies that aren't attached to any principal and aren't used as a permissions
+boundary are marked as unattached. Use --unattached to only show those.
+
+Example:
+
+	awstools iam policylist --unattached -o table`,
+	Run: iampolicylist,
+}
+
+var iampolicylistUnattached bool
+
+func init() {
+	iamCmd.AddCommand(iampolicylistCmd)
+	iampolicylistCmd.Flags().BoolVar(&iampolicylistUnattached, "unattached", false, "Only show policies that aren't attached to any principal")
+}
+
+func iampolicylist(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	resultTitle := "Customer managed IAM policies for account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	policies := helpers.GetCustomerManagedPolicies(awsConfig.IamClient())
+	keys := []string{nameColumn, "Path", "Default Version", "Attachments", "Boundary Usage", "Users", "Groups", "Roles", "Unattached"}
+	if settings.IsVerbose() {
+		keys = append(keys, "ARN", "Created", "Updated")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	output.Settings.SortKey = nameColumn
+	for _, policy := range policies {
+		if iampolicylistUnattached && !policy.IsUnattached() {
+			continue
+		}
+		content := make(map[string]any)
+		content[nameColumn] = policy.Name
+		content["Path"] = policy.Path
+		content["Default Version"] = policy.DefaultVersion
+		content["Attachments"] = policy.AttachmentCount
+		content["Boundary Usage"] = policy.BoundaryUsageCount
+		content["Users"] = policy.Users
+		content["Groups"] = policy.Groups
+		content["Roles"] = policy.Roles
+		content["Unattached"] = policy.IsUnattached()
+		content["ARN"] = policy.Arn
+		content["Created"] = policy.Created.Format(time.RFC3339)
+		content["Updated"] = policy.Updated.Format(time.RFC3339)
+		output.AddContents(content)
+	}
+	output.Write()
+}
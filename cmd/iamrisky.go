@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamriskyCmd represents the iam risky command
+var iamriskyCmd = &cobra.Command{
+	Use:   "risky",
+	Short: "Find admin-equivalent and privilege escalation permissions",
+	Long: `Scans the policies of all IAM roles, users, and groups in the account for risky
+permissions:
+
+  - Admin-equivalent: statements that allow all actions on all resources
+  - PassRole on all resources: statements that allow passing any role to a service
+  - Full service access: statements that allow all actions of a service (e.g. s3:*)
+  - Privilege escalation: known combinations of actions that allow a principal to
+    gain more permissions, such as iam:CreatePolicyVersion, iam:AttachRolePolicy,
+    or iam:PassRole together with lambda:UpdateFunctionCode. Creating access keys
+    or console passwords only counts when it's allowed for other users, so
+    self-service policies scoped to user/${aws:username} aren't reported
+
+Every finding names the principal, policy, and statement that grants it. Policies
+that users inherit from groups are reported for the group, but are taken into
+account for the privilege escalation paths of the user. Conditions, permissions
+boundaries, and SCPs are not taken into account.
+
+Example:
+
+	awstools iam risky -o table`,
+	Run: iamrisky,
+}
+
+func init() {
+	iamCmd.AddCommand(iamriskyCmd)
+}
+
+func iamrisky(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	resultTitle := "Risky IAM permissions in account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	principals, err := helpers.GetIAMPrincipals(awsConfig.IamClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"Principal", "Type", "Risk", "Description", "Statements"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	for _, finding := range helpers.FindRiskyPermissions(principals) {
+		content := make(map[string]any)
+		content["Principal"] = finding.Principal
+		content["Type"] = finding.PrincipalType
+		risk := finding.Risk
+		if finding.Risk == helpers.IAMRiskAdmin || finding.Risk == helpers.IAMRiskPrivilegeEscalation {
+			risk = emojiPrefix("🚨 ", risk, output.Settings.UseEmoji)
+		} else {
+			risk = emojiPrefix("⚠️ ", risk, output.Settings.UseEmoji)
+		}
+		content["Risk"] = risk
+		content["Description"] = finding.Description
+		statements := make([]string, 0, len(finding.Statements))
+		for _, statement := range finding.Statements {
+			statements = append(statements, statement.String())
+		}
+		content["Statements"] = statements
+		output.AddContents(content)
+	}
+	output.Write()
+}
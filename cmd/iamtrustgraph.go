@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/ArjenSchwarz/go-output/drawio"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/spf13/cobra"
+)
+
+const canAssumeColumn = "Can Assume"
+
+// iamtrustgraphCmd represents the iam trust-graph command
+var iamtrustgraphCmd = &cobra.Command{
+	Use:   "trust-graph",
+	Short: "Show who can assume which IAM role",
+	Long: `Shows the trust relationships of all IAM roles in the account, based on their
+trust policies. This includes services, SAML and OIDC federation providers, principals
+in the same account, and principals in other accounts. Account IDs are translated
+using the namefile when one is configured.
+
+Trust relationships that should be reviewed are highlighted:
+
+  - trust policies with a wildcard (*) principal
+  - cross-account principals without an sts:ExternalId condition
+
+Roles that can be assumed by other roles in the account form role chains, which are
+shown in a separate table.
+
+The dot, mermaid, and drawio output formats render the relationships as a graph,
+with the roles that have findings marked in their name.
+
+Examples:
+
+	awstools iam trust-graph -o table
+	awstools iam trust-graph -o dot | dot -Tpng -o trust.png
+	awstools iam trust-graph -o mermaid`,
+	Run: iamtrustgraph,
+}
+
+func init() {
+	iamCmd.AddCommand(iamtrustgraphCmd)
+}
+
+func iamtrustgraph(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	accountID := helpers.GetAccountID(awsConfig.StsClient())
+	roles := helpers.GetRoleDetails(false, awsConfig.IamClient())
+	relationships := helpers.GetTrustRelationships(roles, accountID)
+	resultTitle := "IAM role trust relationships for account " + getName(accountID)
+	if settings.IsDrawIO() || settings.NewOutputSettings().NeedsFromToColumns() {
+		printTrustGraph(resultTitle, roles, relationships, accountID)
+		return
+	}
+	printTrustRelationships(resultTitle, relationships, accountID)
+	printRoleChains(helpers.FindRoleChains(relationships))
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
+
+func printTrustRelationships(title string, relationships []helpers.IAMTrustRelationship, accountID string) {
+	keys := []string{"Role", "Principal Type", "Principal", "Account", "Actions", "Conditional", "Findings"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, relationship := range relationships {
+		content := make(map[string]any)
+		content["Role"] = relationship.Role
+		content["Principal Type"] = relationship.PrincipalType
+		content["Principal"] = trustPrincipalLabel(relationship, accountID)
+		content["Account"] = ""
+		if relationship.AccountID != "" {
+			content["Account"] = getNameWithID(relationship.AccountID)
+		}
+		content["Actions"] = relationship.Actions
+		content["Conditional"] = relationship.Conditional
+		findings := make([]string, 0, len(relationship.Findings))
+		for _, finding := range relationship.Findings {
+			findings = append(findings, emojiPrefix("⚠️ ", finding, output.Settings.UseEmoji))
+		}
+		content["Findings"] = findings
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
+
+func printRoleChains(chains [][]string) {
+	keys := []string{"Start", "Chain", "Length"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "IAM role chains"
+	output.Settings.SeparateTables = true
+	for _, chain := range chains {
+		content := make(map[string]any)
+		content["Start"] = chain[0]
+		content["Chain"] = strings.Join(chain, " -> ")
+		content["Length"] = len(chain)
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
+
+// printTrustGraph shows every principal and role as a node, with a
+// connection from each principal to the roles it can assume
+func printTrustGraph(title string, roles []helpers.IAMRole, relationships []helpers.IAMTrustRelationship, accountID string) {
+	keys := []string{nameColumn, "Type", canAssumeColumn}
+	if settings.IsDrawIO() {
+		keys = append(keys, "Image")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	if settings.IsDrawIO() {
+		output.Settings.DrawIOHeader = createTrustGraphDrawIOHeader()
+	}
+	if output.Settings.NeedsFromToColumns() {
+		output.Settings.AddFromToColumns(nameColumn, canAssumeColumn)
+	}
+	findings := make(map[string][]string)
+	for _, relationship := range relationships {
+		for _, finding := range relationship.Findings {
+			if !contains(findings[relationship.Role], finding) {
+				findings[relationship.Role] = append(findings[relationship.Role], finding)
+			}
+		}
+	}
+	roleLabel := func(role string) string {
+		if len(findings[role]) > 0 {
+			return fmt.Sprintf("%s [%s]", role, strings.Join(findings[role], ", "))
+		}
+		return role
+	}
+	nodeTypes := make(map[string]string)
+	canAssume := make(map[string][]string)
+	for _, role := range roles {
+		nodeTypes[roleLabel(role.Name)] = helpers.IAMObjectTypeRole
+	}
+	for _, relationship := range relationships {
+		principal := trustPrincipalLabel(relationship, accountID)
+		if name, ok := relationship.PrincipalRoleName(); ok {
+			principal = roleLabel(name)
+		}
+		if _, ok := nodeTypes[principal]; !ok {
+			nodeTypes[principal] = trustPrincipalType(relationship)
+		}
+		target := roleLabel(relationship.Role)
+		if !contains(canAssume[principal], target) {
+			canAssume[principal] = append(canAssume[principal], target)
+		}
+	}
+	nodes := make([]string, 0, len(nodeTypes))
+	for node := range nodeTypes {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	for _, node := range nodes {
+		content := make(map[string]any)
+		content[nameColumn] = node
+		content["Type"] = nodeTypes[node]
+		content[canAssumeColumn] = canAssume[node]
+		if settings.IsDrawIO() {
+			content["Image"] = trustGraphShape(nodeTypes[node])
+		}
+		output.AddContents(content)
+	}
+	output.Write()
+}
+
+// trustPrincipalLabel returns a readable name for the principal of a trust relationship
+func trustPrincipalLabel(relationship helpers.IAMTrustRelationship, accountID string) string {
+	switch {
+	case relationship.Principal == "*":
+		return "Anyone (*)"
+	case relationship.IsAccountPrincipal():
+		return "Account " + getNameWithID(relationship.AccountID)
+	}
+	parsed, err := arn.Parse(relationship.Principal)
+	if err != nil {
+		return relationship.Principal
+	}
+	if relationship.PrincipalType == helpers.IAMPrincipalTypeAWS && parsed.AccountID != accountID {
+		return fmt.Sprintf("%s in %s", parsed.Resource, getNameWithID(parsed.AccountID))
+	}
+	return parsed.Resource
+}
+
+// trustPrincipalType returns the node type of a principal in the trust graph
+func trustPrincipalType(relationship helpers.IAMTrustRelationship) string {
+	switch {
+	case relationship.Principal == "*":
+		return "Wildcard"
+	case relationship.IsAccountPrincipal():
+		return "Account"
+	case strings.Contains(relationship.Principal, ":user/"):
+		return helpers.IAMObjectTypeUser
+	case strings.Contains(relationship.Principal, ":role/"), strings.Contains(relationship.Principal, ":assumed-role/"):
+		return helpers.IAMObjectTypeRole
+	}
+	return relationship.PrincipalType
+}
+
+func trustGraphShape(nodetype string) string {
+	switch nodetype {
+	case helpers.IAMObjectTypeRole:
+		return drawio.AWSShape("Security Identity Compliance", "Role")
+	case helpers.IAMObjectTypeUser:
+		return drawio.AWSShape("General Resources", "User")
+	case "Account":
+		return drawio.AWSShape("Management Governance", "Account")
+	case helpers.IAMPrincipalTypeFederated:
+		return drawio.AWSShape("General Resources", "SAML Token")
+	case "Wildcard":
+		return drawio.AWSShape("General Resources", "Internet")
+	}
+	return drawio.AWSShape("General Resources", "General")
+}
+
+func createTrustGraphDrawIOHeader() drawio.Header {
+	drawioheader := drawio.DefaultHeader()
+	drawioheader.SetHeightAndWidth("78", "78")
+	drawioheader.SetLayout(drawio.LayoutHorizontalFlow)
+	connection := drawio.NewConnection()
+	connection.Invert = false
+	connection.From = canAssumeColumn
+	connection.To = nameColumn
+	connection.Label = "Can assume"
+	drawioheader.AddConnection(connection)
+	return drawioheader
+}
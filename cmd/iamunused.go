@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// iamunusedCmd represents the iam unused command
+var iamunusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "Report IAM roles, users, and groups that aren't used",
+	Long: `Reports the IAM principals that are candidates for clean-up:
+
+  - roles that haven't been used within the threshold, based on their last used date
+  - users whose password and access keys haven't been used within the threshold
+  - groups without any members
+
+Principals that were never used are only reported when they were created before
+the threshold. Service-linked roles are shown in a separate table, as they can
+only be deleted through the service that created them.
+
+Example:
+
+	awstools iam unused --days 180 -o table`,
+	Run: iamunused,
+}
+
+var iamunusedDays int
+
+func init() {
+	iamCmd.AddCommand(iamunusedCmd)
+	iamunusedCmd.Flags().IntVar(&iamunusedDays, "days", 90, "The number of days without use after which a principal is considered unused")
+}
+
+func iamunused(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	account := getName(helpers.GetAccountID(awsConfig.StsClient()))
+	report := helpers.GetUnusedIAMPrincipals(iamunusedDays, awsConfig.IamClient())
+	now := time.Now()
+	principals := append(append(report.Roles, report.Users...), report.EmptyGroups...)
+	printUnusedPrincipals(fmt.Sprintf("IAM principals unused for %d days in account %s", iamunusedDays, account), principals, now)
+	printUnusedPrincipals(fmt.Sprintf("Service-linked roles unused for %d days in account %s", iamunusedDays, account), report.ServiceLinkedRoles, now)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
+
+func printUnusedPrincipals(title string, principals []helpers.IAMUnusedPrincipal, now time.Time) {
+	keys := []string{nameColumn, "Type", "Created", "Last Used", "Last Used Region", "Used Via", "Days Inactive", "Reason"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, principal := range principals {
+		content := make(map[string]any)
+		content[nameColumn] = principal.Name
+		content["Type"] = principal.Type
+		content["Created"] = principal.Created.Format(time.RFC3339)
+		content["Last Used"] = "Never"
+		if !principal.NeverUsed() {
+			content["Last Used"] = principal.LastUsed.Format(time.RFC3339)
+		}
+		content["Last Used Region"] = principal.LastUsedRegion
+		content["Used Via"] = principal.UsedVia
+		switch principal.Type {
+		case helpers.IAMObjectTypeGroup:
+			content["Days Inactive"] = ""
+			content["Reason"] = "No members"
+		default:
+			content["Days Inactive"] = principal.DaysInactive(now)
+			content["Reason"] = "Not used within threshold"
+			if principal.NeverUsed() {
+				content["Reason"] = "Never used"
+			}
+		}
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// orgaccountsCmd represents the organizations accounts command
+var orgaccountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Get an inventory of all accounts in the organization",
+	Long: `Lists every account in the organization with its email, status, the path of
+OUs it is in, its tags, the services it is a delegated administrator for, and
+the policies of every enabled policy type that are attached directly to it.
+
+Policies attached to the OUs above an account are not included; use
+organizations scps --account to see the SCPs an account inherits.
+
+This needs to be run from the management account or a delegated administrator
+account.
+
+Examples:
+
+	awstools organizations accounts -o table
+	awstools organizations accounts -o csv --file accounts.csv`,
+	Run: orgaccounts,
+}
+
+func init() {
+	organizationsCmd.AddCommand(orgaccountsCmd)
+}
+
+func orgaccounts(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.OrganizationsClient()
+	organization, err := helpers.GetFullOrganization(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if err := organization.AddTags(svc); err != nil {
+		log.Fatal(err.Error())
+	}
+	delegated, err := helpers.GetDelegatedAdministrators(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	var policies []helpers.OrganizationPolicy
+	for _, policytype := range organization.PolicyTypes {
+		typePolicies, err := helpers.GetOrganizationPolicies(policytype, svc)
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		policies = append(policies, typePolicies...)
+	}
+	accounts := helpers.GetOrganizationAccounts(organization, delegated, policies)
+	keys := []string{"ID", nameColumn, "Email", "Status", "Path", "Joined Method", "Joined", "Tags", "Delegated Administrator For", "Policies"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "AWS Organization Accounts"
+	for _, account := range accounts {
+		content := make(map[string]any)
+		content["ID"] = account.ID
+		content[nameColumn] = account.Name
+		content["Email"] = account.Email
+		content["Status"] = account.Status
+		content["Path"] = account.Path
+		content["Joined Method"] = account.JoinedMethod
+		content["Joined"] = joinedDate(account.JoinedTimestamp)
+		content["Tags"] = account.GetTagList()
+		content["Delegated Administrator For"] = account.DelegatedServices
+		content["Policies"] = account.GetPolicyNames()
+		output.AddContents(content)
+	}
+	output.Write()
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/spf13/cobra"
+)
+
+// orgaioptoutCmd represents the organizations ai-opt-out-policies command
+var orgaioptoutCmd = &cobra.Command{
+	Use:   "ai-opt-out-policies",
+	Short: "Show the AI services opt-out policies and the effective opt-out settings of an account",
+	Long: `Lists all AI services opt-out policies in the organization and the roots, OUs,
+and accounts they are attached to.
+
+With --account, the opt-out policies that apply to that account are shown
+instead: every level from the root through each OU down to the account with
+the opt-out policies attached to it, followed by the effective opt-out setting
+of every service for the account. The default applies to every service that
+isn't listed separately.
+
+This needs to be run from the management account or a delegated administrator
+account.
+
+Examples:
+
+	awstools organizations ai-opt-out-policies -o table
+	awstools organizations ai-opt-out-policies --account 123456789012 -o table`,
+	Run: orgaioptout,
+}
+
+var orgaioptoutAccount string
+
+func init() {
+	organizationsCmd.AddCommand(orgaioptoutCmd)
+	orgaioptoutCmd.Flags().StringVar(&orgaioptoutAccount, "account", "", "Show the opt-out policies inherited by this account and its effective opt-out settings")
+}
+
+func orgaioptout(_ *cobra.Command, _ []string) {
+	orgManagementPolicies("AI Services Opt-Out", types.PolicyTypeAiservicesOptOutPolicy, orgaioptoutAccount, printEffectiveAIOptOutPolicy)
+}
+
+func printEffectiveAIOptOutPolicy(title string, content string) {
+	services, err := helpers.ParseAIServicesOptOutPolicy(content)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"Service", "Opt Out Policy"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, service := range services {
+		content := make(map[string]any)
+		content["Service"] = service.Service
+		content["Opt Out Policy"] = service.Setting
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
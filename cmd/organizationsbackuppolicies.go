@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/spf13/cobra"
+)
+
+// orgbackuppoliciesCmd represents the organizations backup-policies command
+var orgbackuppoliciesCmd = &cobra.Command{
+	Use:   "backup-policies",
+	Short: "Show the backup policies and the effective backup policy of an account",
+	Long: `Lists all backup policies in the organization and the roots, OUs, and accounts
+they are attached to.
+
+With --account, the backup policies that apply to that account are shown
+instead: every level from the root through each OU down to the account with
+the backup policies attached to it, followed by the backup plans of the
+effective backup policy of the account with their regions, rules, and the tags
+that select the resources.
+
+This needs to be run from the management account or a delegated administrator
+account.
+
+Examples:
+
+	awstools organizations backup-policies -o table
+	awstools organizations backup-policies --account 123456789012 -o table`,
+	Run: orgbackuppolicies,
+}
+
+var orgbackuppoliciesAccount string
+
+func init() {
+	organizationsCmd.AddCommand(orgbackuppoliciesCmd)
+	orgbackuppoliciesCmd.Flags().StringVar(&orgbackuppoliciesAccount, "account", "", "Show the backup policies inherited by this account and its effective backup policy")
+}
+
+func orgbackuppolicies(_ *cobra.Command, _ []string) {
+	orgManagementPolicies("Backup", types.PolicyTypeBackupPolicy, orgbackuppoliciesAccount, printEffectiveBackupPolicy)
+}
+
+func printEffectiveBackupPolicy(title string, content string) {
+	plans, err := helpers.ParseBackupPolicy(content)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"Plan", "Regions", "Rule", "Schedule", "Target Vault", "Selections"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, plan := range plans {
+		rules := plan.Rules
+		if len(rules) == 0 {
+			rules = []helpers.BackupPlanRule{{}}
+		}
+		for _, rule := range rules {
+			content := make(map[string]any)
+			content["Plan"] = plan.Name
+			content["Regions"] = plan.Regions
+			content["Rule"] = rule.Name
+			content["Schedule"] = rule.Schedule
+			content["Target Vault"] = rule.TargetVault
+			content["Selections"] = plan.Selections
+			output.AddContents(content)
+		}
+	}
+	output.AddToBuffer()
+}
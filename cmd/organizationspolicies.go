@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// orgManagementPolicies lists the policies of the type or, when an account
+// is provided, shows the policies the account inherits followed by its
+// effective policy through printEffective
+func orgManagementPolicies(name string, policytype types.PolicyType, account string, printEffective func(title string, content string)) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.OrganizationsClient()
+	policies, err := helpers.GetOrganizationPolicies(policytype, svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if account == "" {
+		printOrganizationPolicies(name+" Policies", policies)
+		return
+	}
+	organization, err := helpers.GetFullOrganization(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	levels, err := helpers.GetPolicyInheritance(organization, account, policies)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	effective, err := helpers.GetEffectivePolicy(types.EffectivePolicyType(policytype), account, svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if effective == "" {
+		effective = "{}"
+	}
+	accountName := getName(account)
+	printPolicyInheritance(name+" Policy inheritance for account "+accountName, levels)
+	printEffective("Effective "+name+" Policy for account "+accountName, effective)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/spf13/cobra"
+)
+
+// orgscpsCmd represents the organizations scps command
+var orgscpsCmd = &cobra.Command{
+	Use:   "scps",
+	Short: "Show the Service Control Policies and where they apply",
+	Long: `Lists all Service Control Policies (SCPs) in the organization and the roots,
+OUs, and accounts they are attached to.
+
+With --account, the SCPs that apply to that account are shown instead: every
+level from the root through each OU down to the account with the SCPs attached
+to it, followed by the combined Deny statements of all those SCPs. An action is
+only allowed if every level has an SCP that allows it, and any of the Deny
+statements blocks the actions it matches.
+
+This needs to be run from the management account or a delegated administrator
+account.
+
+Examples:
+
+	awstools organizations scps -o table
+	awstools organizations scps --account 123456789012 -o table`,
+	Run: orgscps,
+}
+
+var orgscpsAccount string
+
+func init() {
+	organizationsCmd.AddCommand(orgscpsCmd)
+	orgscpsCmd.Flags().StringVar(&orgscpsAccount, "account", "", "Show the SCPs inherited by this account and their combined Deny statements")
+}
+
+func orgscps(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.OrganizationsClient()
+	policies, err := helpers.GetOrganizationPolicies(types.PolicyTypeServiceControlPolicy, svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	if orgscpsAccount == "" {
+		printOrganizationPolicies("Service Control Policies", policies)
+		return
+	}
+	organization, err := helpers.GetFullOrganization(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	levels, err := helpers.GetPolicyInheritance(organization, orgscpsAccount, policies)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	denies, err := helpers.GetEffectiveSCPDenies(levels)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	account := getName(orgscpsAccount)
+	printPolicyInheritance("SCP inheritance for account "+account, levels)
+	printSCPDenies("Effective SCP Deny statements for account "+account, denies)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
+
+func printOrganizationPolicies(title string, policies []helpers.OrganizationPolicy) {
+	keys := []string{nameColumn, "ID", "AWS Managed", "Description", "Targets"}
+	if settings.IsVerbose() {
+		keys = append(keys, "Content")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	for _, policy := range policies {
+		content := make(map[string]any)
+		content[nameColumn] = policy.Name
+		content["ID"] = policy.ID
+		content["AWS Managed"] = policy.AWSManaged
+		content["Description"] = policy.Description
+		content["Targets"] = policy.GetTargetNames()
+		content["Content"] = policy.Content
+		output.AddContents(content)
+	}
+	output.Write()
+}
+
+func printPolicyInheritance(title string, levels []helpers.OrganizationPolicyLevel) {
+	keys := []string{"Level", "Type", "Policies"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, level := range levels {
+		content := make(map[string]any)
+		content["Level"] = level.Entry.String()
+		content["Type"] = level.Entry.Type
+		policies := make([]string, 0, len(level.Policies))
+		for _, policy := range level.Policies {
+			policies = append(policies, policy.Name)
+		}
+		content["Policies"] = policies
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
+
+func printSCPDenies(title string, denies []helpers.SCPStatementMatch) {
+	keys := []string{"Policy", "Inherited From", "Statement", "Actions", "Resources", "Condition"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, deny := range denies {
+		content := make(map[string]any)
+		content["Policy"] = deny.PolicyName
+		content["Inherited From"] = deny.InheritedFrom.String()
+		content["Statement"] = deny.String()
+		content["Actions"] = deny.Statement.GetActions()
+		content["Resources"] = deny.Statement.GetResources()
+		content["Condition"] = deny.Statement.GetCondition()
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
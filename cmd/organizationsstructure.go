@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/ArjenSchwarz/awstools/config"
 	"github.com/ArjenSchwarz/awstools/helpers"
@@ -19,14 +21,27 @@ var structureCmd = &cobra.Command{
 Examples:
 
 	awstools organizations structure -o dot | dot -Tpng -o structure.png
+	awstools organizations structure -o mermaid
 	awstools organizations structure -o drawio | pbcopy
+	awstools organizations structure --tree
+	awstools organizations structure --verbose -o csv
 
-Using the dot output format you can turn this into an image, and using drawio you will get a CSV that you can import into draw.io with its CSV import functionality. `,
+Using the dot output format you can turn this into an image, mermaid gives a
+flowchart you can embed in Markdown, and using drawio you will get a CSV that you
+can import into draw.io with its CSV import functionality. With --tree the
+structure is printed as an indented text tree instead.
+
+Accounts that aren't active have their status added to their name, and in
+draw.io suspended accounts are shown faded. With --verbose the email, status,
+join method and date, tags, and OU path of every account are included as well.`,
 	Run: orgstructure,
 }
 
+var orgstructureTree bool
+
 func init() {
 	organizationsCmd.AddCommand(structureCmd)
+	structureCmd.Flags().BoolVar(&orgstructureTree, "tree", false, "Print the structure as an indented text tree")
 }
 
 func orgstructure(_ *cobra.Command, _ []string) {
@@ -36,7 +51,17 @@ func orgstructure(_ *cobra.Command, _ []string) {
 	if err != nil {
 		log.Fatal(err.Error())
 	}
+	if orgstructureTree {
+		fmt.Print(organization.Tree())
+		return
+	}
 	keys := []string{"Name", "Type", childrenColumn}
+	if settings.IsVerbose() {
+		if err := organization.AddTags(awsConfig.OrganizationsClient()); err != nil {
+			log.Fatal(err.Error())
+		}
+		keys = append(keys, "Email", "Status", "Joined Method", "Joined", "Tags", "Path")
+	}
 	if settings.IsDrawIO() {
 		keys = append(keys, "Image")
 	}
@@ -59,15 +84,26 @@ func traverseOrgStructureEntry(entry helpers.OrganizationEntry, output *format.O
 		"ACCOUNT":             drawio.AWSShape("Management Governance", "Account"),
 	}
 	content := make(map[string]any)
-	content["Name"] = entry.String()
+	content["Name"] = entry.DisplayName()
 	content["Type"] = entry.Type
-	content[childrenColumn] = entry.String()
+	content[childrenColumn] = entry.DisplayName()
+	if settings.IsVerbose() {
+		content["Email"] = entry.Email
+		content["Status"] = entry.Status
+		content["Joined Method"] = entry.JoinedMethod
+		content["Joined"] = joinedDate(entry.JoinedTimestamp)
+		content["Tags"] = entry.GetTagList()
+		content["Path"] = entry.Path
+	}
 	if settings.IsDrawIO() {
 		content["Image"] = imageConversion[entry.Type]
+		if entry.IsSuspended() {
+			content["Image"] = imageConversion[entry.Type] + "opacity=40;"
+		}
 	}
 	children := []string{}
 	for _, child := range entry.Children {
-		children = append(children, child.String())
+		children = append(children, child.DisplayName())
 		traverseOrgStructureEntry(child, output)
 	}
 	content[childrenColumn] = children
@@ -75,6 +111,13 @@ func traverseOrgStructureEntry(entry helpers.OrganizationEntry, output *format.O
 	output.AddHolder(holder)
 }
 
+func joinedDate(joined time.Time) string {
+	if joined.IsZero() {
+		return ""
+	}
+	return joined.Format(time.DateOnly)
+}
+
 func createOrganizationsStructureDrawIOHeader() drawio.Header {
 	drawioheader := drawio.DefaultHeader()
 	drawioheader.SetHeightAndWidth("78", "78")
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/spf13/cobra"
+)
+
+// orgtagpoliciesCmd represents the organizations tag-policies command
+var orgtagpoliciesCmd = &cobra.Command{
+	Use:   "tag-policies",
+	Short: "Show the tag policies and the effective tag policy of an account",
+	Long: `Lists all tag policies in the organization and the roots, OUs, and accounts
+they are attached to.
+
+With --account, the tag policies that apply to that account are shown instead:
+every level from the root through each OU down to the account with the tag
+policies attached to it, followed by the effective tag policy of the account.
+For every tag key in the effective policy this shows the allowed values and the
+resource types for which noncompliant tagging operations are blocked.
+
+This needs to be run from the management account or a delegated administrator
+account.
+
+Examples:
+
+	awstools organizations tag-policies -o table
+	awstools organizations tag-policies --account 123456789012 -o table`,
+	Run: orgtagpolicies,
+}
+
+var orgtagpoliciesAccount string
+
+func init() {
+	organizationsCmd.AddCommand(orgtagpoliciesCmd)
+	orgtagpoliciesCmd.Flags().StringVar(&orgtagpoliciesAccount, "account", "", "Show the tag policies inherited by this account and its effective tag policy")
+}
+
+func orgtagpolicies(_ *cobra.Command, _ []string) {
+	orgManagementPolicies("Tag", types.PolicyTypeTagPolicy, orgtagpoliciesAccount, printEffectiveTagPolicy)
+}
+
+func printEffectiveTagPolicy(title string, content string) {
+	rules, err := helpers.ParseTagPolicy(content)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"Tag Key", "Allowed Values", "Enforced", "Enforced For"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = title
+	output.Settings.SeparateTables = true
+	for _, rule := range rules {
+		content := make(map[string]any)
+		content["Tag Key"] = rule.Key
+		content["Allowed Values"] = rule.AllowedValues
+		content["Enforced"] = rule.IsEnforced()
+		content["Enforced For"] = rule.EnforcedFor
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
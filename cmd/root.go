@@ -3,6 +3,7 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
 
@@ -104,7 +105,17 @@ func initConfig() {
 	}
 }
 
-// getName looks for the name of a resource in the namefile and returns that
+// resolvedNames contains names looked up through AWS APIs, such as those of
+// SSO users and groups. Names in the namefile take precedence over these.
+var resolvedNames = make(map[string]string)
+
+// addResolvedNames makes the provided names available to getName
+func addResolvedNames(names map[string]string) {
+	maps.Copy(resolvedNames, names)
+}
+
+// getName looks for the name of a resource in the namefile and returns that.
+// If it's not in the namefile, a name resolved through AWS is used instead.
 func getName(id string) string {
 	if settings.GetString("output.namefile") != "" {
 		nameFile, err := os.ReadFile(settings.GetString("output.namefile"))
@@ -120,6 +131,9 @@ func getName(id string) string {
 			return val
 		}
 	}
+	if val, ok := resolvedNames[id]; ok {
+		return val
+	}
 	return id
 }
 
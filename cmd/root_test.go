@@ -109,3 +109,28 @@ func TestInitConfig_SilentOnReadFailure_T694(t *testing.T) {
 		t.Errorf("did not expect 'Using config file:' on failed read, got stderr=%q", out)
 	}
 }
+
+func TestGetName_NamefileOverridesResolvedNames(t *testing.T) {
+	defer resetViperState(t)()
+	origResolved := resolvedNames
+	defer func() { resolvedNames = origResolved }()
+	resolvedNames = make(map[string]string)
+
+	namefile := filepath.Join(t.TempDir(), "names.json")
+	if err := os.WriteFile(namefile, []byte(`{"u-jane":"Jane (namefile)"}`), 0600); err != nil {
+		t.Fatalf("failed to write namefile: %v", err)
+	}
+	viper.Set("output.namefile", namefile)
+	addResolvedNames(map[string]string{"u-jane": "Jane Doe", "g-admins": "Admins"})
+
+	tests := map[string]string{
+		"u-jane":   "Jane (namefile)",
+		"g-admins": "Admins",
+		"u-other":  "u-other",
+	}
+	for id, want := range tests {
+		if got := getName(id); got != want {
+			t.Errorf("getName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
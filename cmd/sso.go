@@ -209,6 +209,26 @@ func init() {
 
 var ssoresourceid string
 
+// resolveSSOPrincipals looks up the users and groups with account
+// assignments in the Identity Store so getName can show their names. The
+// principals are returned keyed by ID for their user names and email
+// addresses. If the lookup fails, a warning is shown and the principal IDs
+// are used instead.
+func resolveSSOPrincipals(awsConfig config.AWSConfig, instance helpers.SSOInstance) map[string]helpers.SSOPrincipal {
+	resolver := helpers.NewSSOPrincipalResolver(instance, awsConfig.IdentityStoreClient())
+	principals, err := instance.GetPrincipals(resolver)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to resolve SSO user and group names, showing their IDs instead: %v\n", err)
+		return nil
+	}
+	names := make(map[string]string, len(principals))
+	for id, principal := range principals {
+		names[id] = principal.Name()
+	}
+	addResolvedNames(names)
+	return principals
+}
+
 // profileGenerator implements the profile-generator command
 func profileGenerator(cmd *cobra.Command, _ []string) {
 	// Parse command line flags
@@ -254,9 +274,9 @@ func profileGenerator(cmd *cobra.Command, _ []string) {
 	displayComprehensiveResults(result, generator, autoApprove)
 }
 
-// confirmProfileAddition asks user to confirm adding profiles
-func confirmProfileAddition(profiles []helpers.GeneratedProfile) bool {
-	fmt.Printf("\nReady to add %d profiles to AWS CLI configuration.\n", len(profiles))
+// confirm shows the message and asks the user whether to continue
+func confirm(message string) bool {
+	fmt.Printf("\n%s\n", message)
 	fmt.Print("Do you want to continue? (y/N): ")
 
 	reader := bufio.NewReader(os.Stdin)
@@ -331,7 +351,7 @@ func displayComprehensiveResults(result *helpers.ProfileGenerationResult, genera
 
 	// Handle profile confirmation and application
 	if !autoApprove && len(result.GeneratedProfiles) > 0 {
-		if !confirmProfileAddition(result.GeneratedProfiles) {
+		if !confirm(fmt.Sprintf("Ready to add %d profiles to AWS CLI configuration.", len(result.GeneratedProfiles))) {
 			fmt.Println("❌ Profile generation cancelled by user.")
 			return
 		}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// ssoAccessMatrixCmd represents the sso access-matrix command
+var ssoAccessMatrixCmd = &cobra.Command{
+	Use:   "access-matrix",
+	Short: "Show which users can access which accounts with which permission sets",
+	Long: `Shows a row for every user, account, and permission set combination in the SSO
+instance. Assignments to groups are expanded into their members using the
+Identity Store, and every row shows whether the user has the access directly
+or through which group. A user that has the same access directly and through
+one or more groups is shown once for each.
+
+You can limit the output with --user (the user name, display name, email, or
+ID), --account (the account ID or its name from the name file), and
+--permission-set (the name or ARN of the permission set). Combine these to
+answer questions like who can get admin access in production.
+
+Examples:
+
+	awstools sso access-matrix -o csv
+	awstools sso access-matrix --account production --permission-set AdministratorAccess -o table
+	awstools sso access-matrix --user jane@example.com -o table`,
+	Run: ssoAccessMatrix,
+}
+
+var ssoaccessmatrixUser string
+var ssoaccessmatrixAccount string
+var ssoaccessmatrixPermissionSet string
+
+func init() {
+	ssoCmd.AddCommand(ssoAccessMatrixCmd)
+	ssoAccessMatrixCmd.Flags().StringVar(&ssoaccessmatrixUser, "user", "", "Only show the access of this user (user name, display name, email, or ID)")
+	ssoAccessMatrixCmd.Flags().StringVar(&ssoaccessmatrixAccount, "account", "", "Only show access to this account (ID or name from the name file)")
+	ssoAccessMatrixCmd.Flags().StringVar(&ssoaccessmatrixPermissionSet, "permission-set", "", "Only show access through this permission set (name or ARN)")
+}
+
+func ssoAccessMatrix(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	ssoInstance, err := helpers.GetSSOAccountInstance(awsConfig.SsoClient())
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	resolver := helpers.NewSSOPrincipalResolver(ssoInstance, awsConfig.IdentityStoreClient())
+	matrix, err := ssoInstance.GetAccessMatrix(resolver)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	keys := []string{"User", "User Name", "Email", "Account", permissionSetColumn, "Access"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "SSO Access Matrix"
+	for _, access := range matrix {
+		if !filteredSSOAccess(access) {
+			continue
+		}
+		content := make(map[string]any)
+		content["User"] = ssoPrincipalName(access.User)
+		content["User Name"] = access.User.UserName
+		content["Email"] = access.User.Email
+		content["Account"] = getName(access.AccountID)
+		content[permissionSetColumn] = access.PermissionSet.Name
+		content["Access"] = access.Via(ssoPrincipalName)
+		output.AddContents(content)
+	}
+	output.Write()
+}
+
+// ssoPrincipalName returns the name of the principal from the name file, or
+// otherwise its name from the Identity Store
+func ssoPrincipalName(principal helpers.SSOPrincipal) string {
+	if name := getName(principal.ID); name != principal.ID {
+		return name
+	}
+	return principal.Name()
+}
+
+func filteredSSOAccess(access helpers.SSOAccess) bool {
+	if ssoaccessmatrixUser != "" && !access.User.Matches(ssoaccessmatrixUser) {
+		return false
+	}
+	if ssoaccessmatrixAccount != "" && ssoaccessmatrixAccount != access.AccountID && ssoaccessmatrixAccount != getName(access.AccountID) {
+		return false
+	}
+	if ssoaccessmatrixPermissionSet != "" && ssoaccessmatrixPermissionSet != access.PermissionSet.Name && ssoaccessmatrixPermissionSet != access.PermissionSet.Arn {
+		return false
+	}
+	return true
+}
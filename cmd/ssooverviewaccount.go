@@ -17,7 +17,7 @@ var ssoOverviewByAccountCmd = &cobra.Command{
 
 You can filter the output to a single account by supplying the --resource-id (-r) flag with the account ID or, if you use a name file, the account alias from the name file.
 
-Verbose mode will add the policies for the permissionsets in the textual output formats drawio output will generate a graph that goes SSO Instance -> Accounts -> Permission Sets -> User/Group You may notice the same permission sets shown multiple times, this is to improve readability not a bug. dot output is currently limited as it shows internal names only
+Verbose mode will add the user name and email address of users and the policies for the permissionsets in the textual output formats drawio output will generate a graph that goes SSO Instance -> Accounts -> Permission Sets -> User/Group You may notice the same permission sets shown multiple times, this is to improve readability not a bug. dot output is currently limited as it shows internal names only
 	`,
 	Run: ssoOverviewByAccount,
 }
@@ -35,9 +35,10 @@ func ssoOverviewByAccount(_ *cobra.Command, _ []string) {
 	if err != nil {
 		panic(err)
 	}
+	principals := resolveSSOPrincipals(awsConfig, ssoInstance)
 	keys := []string{"AccountID", permissionSetColumn, "Principal"}
 	if settings.IsVerbose() {
-		keys = append(keys, "ManagedPolicies", "InlinePolicy")
+		keys = append(keys, "UserName", "Email", "ManagedPolicies", "InlinePolicy")
 	}
 	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
 	output.Settings.Title = resultTitle
@@ -58,6 +59,8 @@ func ssoOverviewByAccount(_ *cobra.Command, _ []string) {
 					content[permissionSetColumn] = assignment.PermissionSet.Name
 					content["Principal"] = getName(assignment.PrincipalID)
 					if settings.IsVerbose() {
+						content["UserName"] = principals[assignment.PrincipalID].UserName
+						content["Email"] = principals[assignment.PrincipalID].Email
 						content["ManagedPolicies"] = assignment.PermissionSet.GetManagedPolicyNames()
 						content["InlinePolicy"] = assignment.PermissionSet.InlinePolicy
 					}
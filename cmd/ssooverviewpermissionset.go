@@ -17,7 +17,7 @@ var ssoOverviewByPermissionSetCmd = &cobra.Command{
 
 You can filter the output to a single permission set by supplying the --resource-id (-r) flag with the permission set name or arn.
 
-Verbose mode will add the policies for the permissionsets in the textual output formats drawio output will generate a graph that goes SSO Instance -> Permission Sets -> Accounts -> User/Group. You may notice the same accounts shown multiple times, this is to improve readability not a bug. dot output is currently limited as it shows internal names only
+Verbose mode will add the user name and email address of users and the policies for the permissionsets in the textual output formats drawio output will generate a graph that goes SSO Instance -> Permission Sets -> Accounts -> User/Group. You may notice the same accounts shown multiple times, this is to improve readability not a bug. dot output is currently limited as it shows internal names only
 	`,
 	Run: ssoOverviewByPermissionSet,
 }
@@ -34,9 +34,10 @@ func ssoOverviewByPermissionSet(_ *cobra.Command, _ []string) {
 	if err != nil {
 		panic(err)
 	}
+	principals := resolveSSOPrincipals(awsConfig, ssoInstance)
 	keys := []string{"PermissionSet", "AccountID", "Principal"}
 	if settings.IsVerbose() {
-		keys = append(keys, "ManagedPolicies", "InlinePolicy")
+		keys = append(keys, "UserName", "Email", "ManagedPolicies", "InlinePolicy")
 	}
 	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
 	output.Settings.Title = resultTitle
@@ -60,6 +61,8 @@ func ssoOverviewByPermissionSet(_ *cobra.Command, _ []string) {
 					content["AccountID"] = getName(account.AccountID)
 					content["Principal"] = getName(assignment.PrincipalID)
 					if settings.IsVerbose() {
+						content["UserName"] = principals[assignment.PrincipalID].UserName
+						content["Email"] = principals[assignment.PrincipalID].Email
 						content["ManagedPolicies"] = assignment.PermissionSet.GetManagedPolicyNames()
 						content["InlinePolicy"] = assignment.PermissionSet.InlinePolicy
 					}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// ssoProvisioningCmd represents the sso provisioning command
+var ssoProvisioningCmd = &cobra.Command{
+	Use:   "provisioning",
+	Short: "Check which permission sets are outdated in accounts and which provisioning failed",
+	Long: `Checks for every permission set to which accounts it is provisioned and in which
+of those accounts the provisioned version is outdated. When a permission set is
+updated, the accounts keep the previous policies until the permission set is
+provisioned again, so outdated accounts silently grant old permissions.
+
+The failed provisioning requests of the instance are listed as well, with the
+reason they failed.
+
+With --reprovision the latest version of the outdated permission sets is
+provisioned to the outdated accounts. You are asked to confirm this first,
+unless you provide --yes as well. All requests are attempted and listed, and
+if any of them can't be submitted the command exits with an error afterwards.
+
+Examples:
+
+	awstools sso provisioning -o table
+	awstools sso provisioning --reprovision`,
+	Run: ssoProvisioning,
+}
+
+var ssoprovisioningReprovision bool
+var ssoprovisioningYes bool
+
+func init() {
+	ssoCmd.AddCommand(ssoProvisioningCmd)
+	ssoProvisioningCmd.Flags().BoolVar(&ssoprovisioningReprovision, "reprovision", false, "Provision the outdated permission sets to the outdated accounts")
+	ssoProvisioningCmd.Flags().BoolVar(&ssoprovisioningYes, "yes", false, "Reprovision without asking for confirmation")
+}
+
+func ssoProvisioning(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.SsoClient()
+	ssoInstance, err := helpers.GetSSOAccountInstance(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	statuses, err := ssoInstance.GetProvisioningStatuses(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	failed, err := ssoInstance.GetFailedProvisioningOperations(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	printProvisioningStatuses(statuses)
+	printFailedProvisioningOperations(ssoInstance, failed)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+	if !ssoprovisioningReprovision {
+		return
+	}
+	outdatedCount := 0
+	for _, status := range statuses {
+		outdatedCount += len(status.OutdatedAccounts)
+	}
+	if outdatedCount == 0 {
+		fmt.Println("No outdated permission sets to reprovision")
+		return
+	}
+	if !ssoprovisioningYes && !confirm(fmt.Sprintf("Ready to reprovision %d outdated permission set and account combinations.", outdatedCount)) {
+		fmt.Println("Reprovisioning cancelled")
+		return
+	}
+	keys := []string{permissionSetColumn, "Account", "Status", "Request ID", "Error"}
+	requests := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	requests.Settings.Title = "Reprovisioning requests"
+	unsubmitted := 0
+	for _, status := range statuses {
+		for _, account := range status.OutdatedAccounts {
+			content := make(map[string]any)
+			content[permissionSetColumn] = status.PermissionSet.Name
+			content["Account"] = getName(account)
+			operation, err := ssoInstance.ReprovisionPermissionSet(status.PermissionSet, account, svc)
+			if err != nil {
+				// Keep going, as the earlier requests have already been submitted
+				unsubmitted++
+				content["Status"] = emojiPrefix("❌ ", "Not submitted", requests.Settings.UseEmoji)
+				content["Error"] = err.Error()
+			} else {
+				content["Status"] = operation.Status
+				content["Request ID"] = operation.RequestID
+			}
+			requests.AddContents(content)
+		}
+	}
+	requests.Write()
+	if unsubmitted > 0 {
+		log.Fatalf("failed to reprovision %d of %d outdated permission set and account combinations", unsubmitted, outdatedCount)
+	}
+}
+
+func printProvisioningStatuses(statuses []helpers.SSOProvisioningStatus) {
+	keys := []string{permissionSetColumn, "Status", "Provisioned Accounts", "Outdated Accounts"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Permission set provisioning status"
+	output.Settings.SeparateTables = true
+	for _, status := range statuses {
+		content := make(map[string]any)
+		content[permissionSetColumn] = status.PermissionSet.Name
+		content["Status"] = emojiPrefix("✅ ", "Up to date", output.Settings.UseEmoji)
+		if status.IsOutdated() {
+			content["Status"] = emojiPrefix("⚠️ ", "Outdated", output.Settings.UseEmoji)
+		}
+		content["Provisioned Accounts"] = len(status.ProvisionedAccounts)
+		outdated := make([]string, 0, len(status.OutdatedAccounts))
+		for _, account := range status.OutdatedAccounts {
+			outdated = append(outdated, getName(account))
+		}
+		content["Outdated Accounts"] = outdated
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
+
+func printFailedProvisioningOperations(instance helpers.SSOInstance, operations []helpers.SSOProvisioningOperation) {
+	keys := []string{"Created", permissionSetColumn, "Account", "Failure Reason", "Request ID"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Failed provisioning requests"
+	output.Settings.SeparateTables = true
+	for _, operation := range operations {
+		content := make(map[string]any)
+		content["Created"] = operation.CreatedDate.Format(time.RFC3339)
+		content[permissionSetColumn] = operation.PermissionSetArn
+		if permissionset, found := instance.GetPermissionSet(operation.PermissionSetArn); found {
+			content[permissionSetColumn] = permissionset.Name
+		}
+		content["Account"] = getName(operation.AccountID)
+		content["Failure Reason"] = operation.FailureReason
+		content["Request ID"] = operation.RequestID
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// ssoShowPermissionSetCmd represents the sso show-permission-set command
+var ssoShowPermissionSetCmd = &cobra.Command{
+	Use:   "show-permission-set <name>",
+	Short: "Show everything a permission set consists of, including its policies",
+	Long: `Shows the full configuration of a permission set, identified by its name or ARN:
+its description, session duration, relay state, tags, and the accounts it is
+provisioned to, followed by every policy that makes up its permissions and the
+statements in those policies.
+
+The policies are the inline policy, the AWS managed policies, the customer
+managed policies, and the permissions boundary. Customer managed policies are
+references to policies that need to exist with the same name and path in every
+account the permission set is provisioned to. Their documents are read from the
+account of the current credentials, and if a policy doesn't exist there it is
+marked as missing.
+
+Verbose mode adds the full policy documents to the list of policies.
+
+Examples:
+
+	awstools sso show-permission-set AdministratorAccess -o table
+	awstools sso show-permission-set ReadOnly --verbose -o json`,
+	Args: cobra.ExactArgs(1),
+	Run:  ssoShowPermissionSet,
+}
+
+func init() {
+	ssoCmd.AddCommand(ssoShowPermissionSetCmd)
+}
+
+func ssoShowPermissionSet(_ *cobra.Command, args []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	svc := awsConfig.SsoClient()
+	ssoInstance, err := helpers.GetSSOAccountInstance(svc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	permissionset, found := ssoInstance.GetPermissionSet(args[0])
+	if !found {
+		log.Fatalf("no permission set found with the name or ARN %s", args[0])
+	}
+	if err := permissionset.AddDetails(svc); err != nil {
+		log.Fatal(err.Error())
+	}
+	iamSvc := awsConfig.IamClient()
+	if err := permissionset.AddPolicyDocuments(awsConfig.AccountID, iamSvc); err != nil {
+		log.Fatal(err.Error())
+	}
+	principal, err := helpers.GetPermissionSetPolicies(permissionset, iamSvc)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	printPermissionSetSummary(permissionset)
+	printPermissionSetPolicies(permissionset, awsConfig.AccountID)
+	printPermissionSetStatements(principal)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
+
+func printPermissionSetSummary(permissionset helpers.SSOPermissionSet) {
+	keys := []string{nameColumn, "ARN", "Description", "Session Duration", "Relay State", "Created", "Tags", "Accounts"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Permission set " + permissionset.Name
+	output.Settings.SeparateTables = true
+	content := make(map[string]any)
+	content[nameColumn] = permissionset.Name
+	content["ARN"] = permissionset.Arn
+	content["Description"] = permissionset.Description
+	content["Session Duration"] = permissionset.SessionDuration
+	content["Relay State"] = permissionset.RelayState
+	content["Created"] = permissionset.CreatedAt.Format(time.RFC3339)
+	tags := make([]string, 0, len(permissionset.Tags))
+	for key, value := range permissionset.Tags {
+		tags = append(tags, key+"="+value)
+	}
+	sort.Strings(tags)
+	content["Tags"] = tags
+	accounts := make([]string, 0, len(permissionset.Accounts))
+	for _, account := range permissionset.Accounts {
+		accounts = append(accounts, getName(account.AccountID))
+	}
+	content["Accounts"] = accounts
+	output.AddContents(content)
+	output.AddToBuffer()
+}
+
+func printPermissionSetPolicies(permissionset helpers.SSOPermissionSet, accountID string) {
+	keys := []string{"Type", nameColumn, "ARN", "Status"}
+	if settings.IsVerbose() {
+		keys = append(keys, "Document")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Policies of permission set " + permissionset.Name
+	output.Settings.SeparateTables = true
+	addPolicy := func(policytype string, name string, arn string, document string) {
+		content := make(map[string]any)
+		content["Type"] = policytype
+		content[nameColumn] = name
+		content["ARN"] = arn
+		content["Status"] = ""
+		if document == "" {
+			content["Status"] = emojiPrefix("⚠️ ", fmt.Sprintf("Missing in account %s", getName(accountID)), output.Settings.UseEmoji)
+		}
+		content["Document"] = document
+		output.AddContents(content)
+	}
+	partition := permissionset.Partition()
+	if permissionset.InlinePolicy != "" {
+		addPolicy("Inline", permissionset.Name, "", permissionset.InlinePolicy)
+	}
+	for _, policy := range permissionset.ManagedPolicies {
+		addPolicy("AWS Managed", policy.Name, policy.Arn, policy.Policy)
+	}
+	for _, policy := range permissionset.CustomerManagedPolicies {
+		addPolicy("Customer Managed", policy.Name, policy.ArnInAccount(partition, accountID), policy.Policy)
+	}
+	if boundary := permissionset.PermissionsBoundary; boundary != nil {
+		arn := boundary.ManagedPolicyArn
+		if boundary.CustomerManagedPolicy != nil {
+			arn = boundary.CustomerManagedPolicy.ArnInAccount(partition, accountID)
+		}
+		addPolicy("Permissions Boundary", boundary.Name(), arn, boundary.Policy)
+	}
+	output.AddToBuffer()
+}
+
+func printPermissionSetStatements(principal helpers.IAMPrincipalPolicies) {
+	keys := []string{"Statement", "Effect", "Actions", "Resources", "Condition"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Statements of permission set " + principal.Name
+	output.Settings.SeparateTables = true
+	policies := principal.Policies
+	if principal.PermissionsBoundary != nil {
+		policies = append(policies, *principal.PermissionsBoundary)
+	}
+	for _, policy := range policies {
+		for index, statement := range policy.Statement {
+			match := helpers.IAMStatementMatch{PolicyName: policy.Name, PolicyType: policy.Type, Index: index, Statement: statement}
+			content := make(map[string]any)
+			content["Statement"] = match.String()
+			content["Effect"] = statement.Effect
+			content["Actions"] = statement.GetActions()
+			content["Resources"] = statement.GetResources()
+			content["Condition"] = statement.GetCondition()
+			output.AddContents(content)
+		}
+	}
+	output.AddToBuffer()
+}
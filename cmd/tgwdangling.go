@@ -1,6 +1,10 @@
 package cmd
 
 import (
+	"fmt"
+	"log"
+	"os"
+
 	"github.com/ArjenSchwarz/awstools/config"
 	"github.com/ArjenSchwarz/awstools/helpers"
 	format "github.com/ArjenSchwarz/go-output"
@@ -15,7 +19,22 @@ var tgwdanglingCmd = &cobra.Command{
 
 	An incomplete route is defined as one that goes in only a single
 	direction. e.g. while VPC1 connects to VPC2, there is no returning
-	connection.`,
+	connection.
+
+	The analysis is done on the Transit Gateway attachments, so attachments of
+	resources owned by other accounts (shared through RAM) are handled the same
+	as local ones. For these, the owner of the resource and the RAM shares that
+	give the account access to the Transit Gateway are shown. As route tables
+	can only be seen by the owner of the Transit Gateway, this needs to be run
+	in that account.
+
+	Each finding is classified as one of:
+	  - Missing association: the target isn't associated with a route table
+	  - Missing propagation: the source doesn't propagate into the target's route table
+	  - One-way route: there is no route back, for example for peering attachments
+	    where routes can't be propagated
+
+	A suggested fix is provided for every finding.`,
 	Run: tgwdangling,
 }
 
@@ -26,37 +45,65 @@ func init() {
 func tgwdangling(_ *cobra.Command, _ []string) {
 	awsConfig := config.DefaultAwsConfig(*settings)
 	resultTitle := "Transit Gateway uni-directional routes"
-	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
-	keys := []string{"VPC", "VPCName", "DestinationVPC", "DestinationName"}
+	ec2Client := awsConfig.Ec2Client()
+	gateways := helpers.GetAllTransitGateways(ec2Client)
+	ownership := helpers.GetTransitGatewayAttachmentOwnership(ec2Client)
+	shares, err := helpers.GetTransitGatewayShares(awsConfig.RAMClient())
+	if err != nil {
+		if !helpers.IsAccessDeniedError(err) {
+			log.Fatal(err.Error())
+		}
+		fmt.Fprintf(os.Stderr, "Unable to read RAM shares, leaving Shared Via empty: %v\n", err)
+	}
+	keys := []string{"VPC", "VPCName", "DestinationVPC", "DestinationName", "Source", "Source Account", "Route Table", "Destinations", "Target", "Target Account", "Shared Via", "Target Route Table", "Issue", "Suggested Fix"}
 	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
 	output.Settings.Title = resultTitle
-	vpcs := make(map[string][]string)
-	for _, gateway := range gateways {
-		for _, routetable := range gateway.RouteTables {
-			for _, assoc := range routetable.SourceAttachments {
-				vpcs[assoc.ResourceID] = []string{}
-				for _, route := range routetable.Routes {
-					vpcs[assoc.ResourceID] = append(vpcs[assoc.ResourceID], route.Attachment.ResourceID)
-				}
+	output.Settings.SortKey = "Source"
+	for _, finding := range helpers.FindDanglingTransitGatewayRoutes(gateways) {
+		content := make(map[string]any)
+		content["VPC"] = finding.Source.ResourceID
+		content["VPCName"] = getName(finding.Source.ResourceID)
+		content["DestinationVPC"] = finding.Target.ResourceID
+		content["DestinationName"] = getName(finding.Target.ResourceID)
+		content["Source"] = tgwAttachmentName(finding.Source)
+		content["Source Account"] = tgwAttachmentOwner(ownership[finding.Source.ID])
+		content["Route Table"] = getNameWithID(finding.RouteTable.ID)
+		content["Destinations"] = finding.Destinations
+		content["Target"] = tgwAttachmentName(finding.Target)
+		targetOwnership := ownership[finding.Target.ID]
+		content["Target Account"] = tgwAttachmentOwner(targetOwnership)
+		var sharedVia []string
+		for _, attachment := range []helpers.TransitGatewayAttachmentOwnership{ownership[finding.Source.ID], targetOwnership} {
+			if attachment.IsCrossAccount() {
+				sharedVia = append(sharedVia, helpers.SharesForAccount(shares, attachment.ResourceOwnerID)...)
 			}
-
 		}
+		content["Shared Via"] = unique(sharedVia)
+		content["Target Route Table"] = getNameWithID(finding.TargetRouteTable)
+		issue := finding.Issue
+		if output.Settings.UseEmoji {
+			issue = "⚠️ " + issue
+		}
+		content["Issue"] = issue
+		content["Suggested Fix"] = finding.Fix
+		output.AddContents(content)
 	}
+	output.Write()
+}
 
-	for vpcid, targets := range vpcs {
-		for _, target := range targets {
-			if !contains(vpcs[target], vpcid) {
-				content := make(map[string]any)
-				content["VPC"] = vpcid
-				content["VPCName"] = getName(vpcid)
-				content["DestinationVPC"] = target
-				content["DestinationName"] = getName(target)
-				holder := format.OutputHolder{Contents: content}
-				output.AddHolder(holder)
-			}
-		}
+func tgwAttachmentName(attachment helpers.TransitGatewayAttachment) string {
+	if attachment.ResourceID == "" {
+		return getNameWithID(attachment.ID)
+	}
+	return getNameWithID(attachment.ResourceID) + " [" + attachment.ID + "]"
+}
 
+func tgwAttachmentOwner(ownership helpers.TransitGatewayAttachmentOwnership) string {
+	if ownership.ResourceOwnerID == "" {
+		return ""
 	}
-	// fmt.Printf("%v", vpcs)
-	output.Write()
+	if ownership.IsCrossAccount() {
+		return getNameWithID(ownership.ResourceOwnerID) + " (cross-account)"
+	}
+	return getNameWithID(ownership.ResourceOwnerID)
 }
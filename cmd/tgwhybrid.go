@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// tgwhybridCmd represents the tgw hybrid command
+var tgwhybridCmd = &cobra.Command{
+	Use:   "hybrid",
+	Short: "Show the health of VPN and Direct Connect attachments",
+	Long: `Show the health of the hybrid connectivity of the Transit Gateways.
+
+For VPN attachments every tunnel is shown with its outside IP address, tunnel
+status, number of accepted routes, and when the status last changed. AWS doesn't
+report the state of the BGP session itself, so the BGP status is based on the
+tunnel status and the number of routes accepted over it.
+For Direct Connect gateway attachments the association state, the Direct Connect
+gateway details, and the allowed prefixes are shown.
+
+Example:
+
+	awstools tgw hybrid -o table --emoji`,
+	Run: tgwhybrid,
+}
+
+func init() {
+	tgwCmd.AddCommand(tgwhybridCmd)
+}
+
+func tgwhybrid(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
+	hybrid, err := helpers.GetHybridConnectivity(gateways, awsConfig.Ec2Client(), awsConfig.DirectConnectClient())
+	if err != nil {
+		if !helpers.IsAccessDeniedError(err) {
+			log.Fatal(err.Error())
+		}
+		fmt.Fprintf(os.Stderr, "Unable to read all hybrid connectivity, the output is incomplete: %v\n", err)
+	}
+	printVPNConnections(hybrid.VPNConnections)
+	printDirectConnectAssociations(hybrid.DirectConnectAssociations)
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	output.Write()
+}
+
+func printVPNConnections(connections []helpers.VPNConnection) {
+	keys := []string{"Transit Gateway", "VPN Connection", "Customer Gateway", "State", "Routing", "Outside IP", "Tunnel Status", "BGP Status", "Accepted Routes", "Last Status Change", "Status Message"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Transit Gateway VPN connections"
+	output.Settings.SeparateTables = true
+	output.Settings.SortKey = "VPN Connection"
+	for _, vpn := range connections {
+		for _, tunnel := range vpn.Tunnels {
+			content := make(map[string]any)
+			content["Transit Gateway"] = getNameWithID(vpn.TransitGatewayID)
+			content["VPN Connection"] = vpnDisplayName(vpn)
+			content["Customer Gateway"] = getNameWithID(vpn.CustomerGatewayID)
+			content["State"] = vpn.State
+			content["Routing"] = vpn.RoutingType()
+			content["Outside IP"] = tunnel.OutsideIP
+			status := tunnel.Status
+			if output.Settings.UseEmoji {
+				if tunnel.IsUp() {
+					status = "✅ " + status
+				} else {
+					status = "❌ " + status
+				}
+			}
+			content["Tunnel Status"] = status
+			content["BGP Status"] = vpn.BGPStatus(tunnel)
+			content["Accepted Routes"] = tunnel.AcceptedRouteCount
+			content["Last Status Change"] = ""
+			if tunnel.LastStatusChange != nil {
+				content["Last Status Change"] = tunnel.LastStatusChange.Format(time.RFC3339)
+			}
+			content["Status Message"] = tunnel.StatusMessage
+			output.AddContents(content)
+		}
+	}
+	output.AddToBuffer()
+}
+
+func vpnDisplayName(vpn helpers.VPNConnection) string {
+	if vpn.Name != "" {
+		return vpn.Name + " (" + vpn.ID + ")"
+	}
+	return getNameWithID(vpn.ID)
+}
+
+func printDirectConnectAssociations(associations []helpers.DirectConnectGatewayAssociation) {
+	keys := []string{"Transit Gateway", "Direct Connect Gateway", "Owner Account", "Amazon Side ASN", "Gateway State", "Association State", "Allowed Prefixes", "Error"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Transit Gateway Direct Connect gateway associations"
+	output.Settings.SeparateTables = true
+	for _, association := range associations {
+		content := make(map[string]any)
+		content["Transit Gateway"] = getNameWithID(association.TransitGatewayID)
+		gateway := getNameWithID(association.DirectConnectGatewayID)
+		if association.GatewayName != "" {
+			gateway = association.GatewayName + " (" + association.DirectConnectGatewayID + ")"
+		}
+		content["Direct Connect Gateway"] = gateway
+		content["Owner Account"] = getNameWithID(association.OwnerAccount)
+		content["Amazon Side ASN"] = association.AmazonSideASN
+		content["Gateway State"] = association.GatewayState
+		state := association.State
+		if output.Settings.UseEmoji {
+			if association.State == "associated" {
+				state = "✅ " + state
+			} else {
+				state = "⚠️ " + state
+			}
+		}
+		content["Association State"] = state
+		content["Allowed Prefixes"] = association.AllowedPrefixes
+		content["Error"] = association.StateChangeError
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// tgwlookupCmd represents the tgw lookup command
+var tgwlookupCmd = &cobra.Command{
+	Use:   "lookup",
+	Short: "Simulate the Transit Gateway route for a destination IP",
+	Long: `Simulate how the Transit Gateway routes traffic from an attachment to a destination IP address.
+
+The route table associated with the source attachment is determined, after which
+a longest prefix match is performed across its static, propagated, and blackhole
+routes. The result shows the target attachment that the traffic is forwarded to,
+or whether it ends up in a blackhole.
+
+The same is then done for the return path, from the target attachment back to the
+source. If you provide the --source flag with the IP address the traffic originates
+from, a full lookup is done for the return path. Otherwise the routes in the target's
+route table that lead back to the source attachment are shown.
+
+The attachment can be provided as either the attachment ID or the ID of the attached
+resource, such as the VPC ID.
+
+Examples:
+
+	awstools tgw lookup --attachment tgw-attach-0123456789abcdef0 --destination 10.1.2.3
+	awstools tgw lookup --attachment vpc-0123456789abcdef0 --destination 10.1.2.3 --source 10.0.0.5 -o table`,
+	Run: tgwlookup,
+}
+
+var tgwlookupAttachment string
+var tgwlookupDestination string
+var tgwlookupSource string
+
+func init() {
+	tgwCmd.AddCommand(tgwlookupCmd)
+	tgwlookupCmd.Flags().StringVar(&tgwlookupAttachment, "attachment", "", "The attachment ID (or attached resource ID) the traffic originates from")
+	tgwlookupCmd.Flags().StringVar(&tgwlookupDestination, "destination", "", "The destination IP address")
+	tgwlookupCmd.Flags().StringVar(&tgwlookupSource, "source", "", "Optional source IP address, used to simulate the return path")
+	_ = tgwlookupCmd.MarkFlagRequired("attachment")
+	_ = tgwlookupCmd.MarkFlagRequired("destination")
+}
+
+func tgwlookup(_ *cobra.Command, _ []string) {
+	for _, ip := range []string{tgwlookupDestination, tgwlookupSource} {
+		if ip != "" && !helpers.IsValidIPAddress(ip) {
+			log.Fatalf("invalid IP address format: %s", ip)
+		}
+	}
+	awsConfig := config.DefaultAwsConfig(*settings)
+	resultTitle := fmt.Sprintf("Transit Gateway route lookup from %s to %s", getNameWithID(tgwlookupAttachment), tgwlookupDestination)
+	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
+	keys := []string{"Direction", "Transit Gateway", "Source", "Route Table", "Destination", "Matched Route", "Route Type", "Target", "Result"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	forward, err := helpers.LookupTransitGatewayRoute(gateways, tgwlookupAttachment, tgwlookupDestination)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	output.AddContents(tgwLookupContent("Forward", forward, lookupResult(forward, "", output.Settings.UseEmoji)))
+	if forward.HasTarget() {
+		output.AddContents(tgwReturnPathContent(gateways, forward, output.Settings.UseEmoji))
+	}
+	output.Write()
+}
+
+// tgwReturnPathContent determines the return path from the target of the
+// forward lookup back to the source attachment
+func tgwReturnPathContent(gateways []helpers.TransitGateway, forward helpers.TransitGatewayRouteLookup, useEmoji bool) map[string]any {
+	target := forward.Route.Attachment
+	if tgwlookupSource != "" {
+		returnpath, err := helpers.LookupTransitGatewayRoute(gateways, target.ID, tgwlookupSource)
+		if err != nil {
+			return map[string]any{"Direction": "Return", "Source": getNameWithID(target.ID), "Destination": tgwlookupSource, "Result": err.Error()}
+		}
+		return tgwLookupContent("Return", returnpath, lookupResult(returnpath, forward.SourceAttachment.ID, useEmoji))
+	}
+	gateway, routetable, source, err := helpers.FindTransitGatewayAttachment(gateways, target.ID)
+	if err != nil {
+		return map[string]any{"Direction": "Return", "Source": getNameWithID(target.ID), "Result": err.Error()}
+	}
+	returnroutes := routetable.RoutesToAttachment(forward.SourceAttachment.ID)
+	cidrs := make([]string, 0, len(returnroutes))
+	for _, route := range returnroutes {
+		cidrs = append(cidrs, route.CIDR)
+	}
+	content := tgwLookupContent("Return", helpers.TransitGatewayRouteLookup{
+		TransitGateway:   gateway,
+		SourceAttachment: source,
+		RouteTable:       routetable,
+	}, "")
+	content["Matched Route"] = cidrs
+	content["Target"] = getNameWithID(forward.SourceAttachment.ID)
+	if len(cidrs) == 0 {
+		content["Result"] = emojiPrefix("❌ ", "No route back to the source attachment", useEmoji)
+	} else {
+		content["Result"] = emojiPrefix("✅ ", "Routes back to the source attachment", useEmoji)
+	}
+	return content
+}
+
+func tgwLookupContent(direction string, lookup helpers.TransitGatewayRouteLookup, result string) map[string]any {
+	content := make(map[string]any)
+	content["Direction"] = direction
+	content["Transit Gateway"] = getNameWithID(lookup.TransitGateway.ID)
+	source := getNameWithID(lookup.SourceAttachment.ID)
+	if lookup.SourceAttachment.ResourceID != "" {
+		source = fmt.Sprintf("%s (%s)", source, getNameWithID(lookup.SourceAttachment.ResourceID))
+	}
+	content["Source"] = source
+	content["Route Table"] = getNameWithID(lookup.RouteTable.ID)
+	content["Destination"] = lookup.Destination
+	content["Matched Route"] = ""
+	content["Route Type"] = ""
+	content["Target"] = ""
+	if lookup.Route != nil {
+		content["Matched Route"] = lookup.Route.CIDR
+		content["Route Type"] = lookup.Route.RouteType
+		if lookup.HasTarget() {
+			content["Target"] = fmt.Sprintf("%s (%s)", getNameWithID(lookup.Route.Attachment.ID), getNameWithID(lookup.Route.Attachment.ResourceID))
+		}
+	}
+	content["Result"] = result
+	return content
+}
+
+// lookupResult summarises the outcome of a lookup. When expectedTarget is
+// provided, the result also reflects whether traffic returns to it.
+func lookupResult(lookup helpers.TransitGatewayRouteLookup, expectedTarget string, useEmoji bool) string {
+	switch {
+	case lookup.Route == nil:
+		return emojiPrefix("❌ ", "No matching route", useEmoji)
+	case lookup.IsBlackhole():
+		return emojiPrefix("❌ ", "Blackhole", useEmoji)
+	case expectedTarget != "" && lookup.Route.Attachment.ID != expectedTarget:
+		return emojiPrefix("⚠️ ", "Asymmetric: returns through "+lookup.Route.Attachment.ID, useEmoji)
+	}
+	return emojiPrefix("✅ ", "Forwarded to "+lookup.Route.Attachment.ID, useEmoji)
+}
+
+func emojiPrefix(emoji string, value string, useEmoji bool) string {
+	if useEmoji {
+		return emoji + value
+	}
+	return value
+}
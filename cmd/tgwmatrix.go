@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// tgwmatrixCmd represents the tgw matrix command
+var tgwmatrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Show the association and propagation matrix of the Transit Gateway",
+	Long: `Show a matrix with the attachments of the Transit Gateway as rows and its
+route tables as columns. Each cell shows whether the attachment is associated
+with the route table and/or propagates its routes into it. Attachments that
+aren't associated with or propagating into any route table are shown with
+empty cells. This makes it easy to review the segmentation of the Transit Gateway.
+
+When the --emoji flag is used, associations are marked with 🔗 and propagations
+with 📣. Each Transit Gateway is shown in a separate table.
+
+Examples:
+
+	awstools tgw matrix -o table --emoji
+	awstools tgw matrix -o csv`,
+	Run: tgwmatrix,
+}
+
+func init() {
+	tgwCmd.AddCommand(tgwmatrixCmd)
+}
+
+func tgwmatrix(_ *cobra.Command, _ []string) {
+	awsConfig := config.DefaultAwsConfig(*settings)
+	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
+	attachments := helpers.GetTransitGatewayAttachmentOwnership(awsConfig.Ec2Client())
+	output := format.OutputArray{Settings: settings.NewOutputSettings()}
+	for _, gateway := range gateways {
+		printTgwMatrix(gateway, gateway.Attachments(attachments))
+	}
+	output.Write()
+}
+
+func printTgwMatrix(gateway helpers.TransitGateway, attachments []helpers.TransitGatewayAttachment) {
+	routetables := gateway.RouteTableIDs()
+	keys := []string{"Attachment", "Resource", "Type"}
+	for _, routetable := range routetables {
+		keys = append(keys, getNameWithID(routetable))
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = fmt.Sprintf("Transit Gateway association and propagation matrix for %s", getNameWithID(gateway.ID))
+	output.Settings.SeparateTables = true
+	for _, entry := range gateway.AttachmentMatrix(attachments) {
+		content := make(map[string]any)
+		content["Attachment"] = getNameWithID(entry.Attachment.ID)
+		content["Resource"] = getNameWithID(entry.Attachment.ResourceID)
+		content["Type"] = entry.Attachment.ResourceType
+		for _, routetable := range routetables {
+			content[getNameWithID(routetable)] = tgwMatrixCell(entry, routetable, output.Settings.UseEmoji)
+		}
+		output.AddContents(content)
+	}
+	output.AddToBuffer()
+}
+
+// tgwMatrixCell returns the markers for an attachment's relation to a route table
+func tgwMatrixCell(entry helpers.TransitGatewayAttachmentMatrixEntry, routetableID string, useEmoji bool) string {
+	var markers []string
+	if entry.IsAssociatedWith(routetableID) {
+		markers = append(markers, emojiPrefix("🔗 ", "associated", useEmoji))
+	}
+	if entry.PropagatesInto(routetableID) {
+		markers = append(markers, emojiPrefix("📣 ", "propagates", useEmoji))
+	}
+	return strings.Join(markers, ", ")
+}
@@ -1,6 +1,9 @@
 package cmd
 
 import (
+	"fmt"
+	"log"
+	"os"
 	"strings"
 
 	"github.com/ArjenSchwarz/awstools/config"
@@ -18,22 +21,41 @@ var tgwoverviewCmd = &cobra.Command{
 This can be improved on, but offers a simple text based overview with all relevant information
 
 If you choose the drawio output instead, you get a simple diagram showing the Transit Gateway and all resources (VPCs, VPNs, Direct Connect) attached to it.
+
+Using the --peerings flag, the Transit Gateway peering attachments are followed into the
+other regions, and the routes of the peered Transit Gateways are included as well. Peers
+in other accounts can't be inspected, but are still shown. In addition to the routes an
+overview of the peerings is shown, including the static routes each side has over the
+peering. The drawio and dot output formats then show the inter-region mesh of Transit Gateways.
+
+In verbose mode, the health of VPN and Direct Connect targets is added to the routes.
+If you don't have permission to read the VPN connections or Direct Connect gateways,
+these details are left empty. For full details of these hybrid connections, use the tgw hybrid command.
+
+	awstools tgw overview --peerings -o table
+	awstools tgw overview --peerings -o dot | dot -Tpng -o tgw-mesh.png
 	`,
 	Run: tgwoverview,
 }
 
 var excludeRouteTarget string
 var includeBlackhole bool
+var tgwoverviewPeerings bool
 
 func init() {
 	tgwCmd.AddCommand(tgwoverviewCmd)
 	tgwoverviewCmd.Flags().StringVarP(&excludeRouteTarget, "exclude-target", "e", "", "Optional value to exclude a specific target from the output")
 	tgwoverviewCmd.Flags().BoolVarP(&includeBlackhole, "blackhole-routes", "b", false, "Optional value to include blackhole routes")
+	tgwoverviewCmd.Flags().BoolVar(&tgwoverviewPeerings, "peerings", false, "Follow Transit Gateway peering attachments into other regions")
 }
 
 func tgwoverview(_ *cobra.Command, _ []string) {
 	awsConfig := config.DefaultAwsConfig(*settings)
 	resultTitle := "Transit Gateway Routes in account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	if tgwoverviewPeerings {
+		tgwMeshOverview(awsConfig, resultTitle)
+		return
+	}
 	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
 	keys := []string{"Transit Gateway Account", "Transit Gateway", "Route Table", "CIDR", "Target", "Target Type", "State"}
 	if settings.IsDrawIO() {
@@ -45,41 +67,176 @@ func tgwoverview(_ *cobra.Command, _ []string) {
 	if settings.IsDrawIO() {
 		createTgwOverviewDrawIO(&output, gateways)
 	} else {
-		for _, gateway := range gateways {
-			for _, routetable := range gateway.RouteTables {
-				for _, route := range routetable.Routes {
-					if excludeRouteTarget == route.Attachment.ResourceID {
-						continue
-					}
-					if !includeBlackhole && route.State == "blackhole" {
-						continue
-					}
-					content := make(map[string]any)
-					content["Transit Gateway Account"] = getNameWithID(gateway.AccountID)
-					content["Transit Gateway"] = getNameWithID(gateway.ID)
-					content["Route Table"] = getNameWithID(routetable.ID)
-					content["CIDR"] = route.CIDR
-					if route.Attachment.ResourceID != "" {
-						content["Target"] = getNameWithID(route.Attachment.ResourceID)
+		var hybrid helpers.HybridConnectivity
+		if settings.IsVerbose() {
+			output.Keys = append(output.Keys, "Target Details")
+			var err error
+			hybrid, err = helpers.GetHybridConnectivity(gateways, awsConfig.Ec2Client(), awsConfig.DirectConnectClient())
+			if err != nil {
+				if !helpers.IsAccessDeniedError(err) {
+					log.Fatal(err.Error())
+				}
+				fmt.Fprintf(os.Stderr, "Unable to read all hybrid connectivity, leaving Target Details incomplete: %v\n", err)
+			}
+		}
+		addTgwOverviewRoutes(&output, gateways, hybrid)
+	}
+	output.Write()
+}
+
+// addTgwOverviewRoutes adds the routes of the Transit Gateways to the output.
+// The health of VPN and Direct Connect targets is taken from the hybrid
+// connectivity of each Transit Gateway.
+func addTgwOverviewRoutes(output *format.OutputArray, gateways []helpers.TransitGateway, hybrid helpers.HybridConnectivity) {
+	for _, gateway := range gateways {
+		details := hybrid.Summaries(gateway.ID)
+		for _, routetable := range gateway.RouteTables {
+			for _, route := range routetable.Routes {
+				if excludeRouteTarget == route.Attachment.ResourceID {
+					continue
+				}
+				if !includeBlackhole && route.State == "blackhole" {
+					continue
+				}
+				content := make(map[string]any)
+				content["Transit Gateway Account"] = getNameWithID(gateway.AccountID)
+				content["Transit Gateway"] = getNameWithID(gateway.ID)
+				content["Region"] = gateway.Region
+				content["Route Table"] = getNameWithID(routetable.ID)
+				content["CIDR"] = route.CIDR
+				if route.Attachment.ResourceID != "" {
+					content["Target"] = getNameWithID(route.Attachment.ResourceID)
+				} else {
+					content["Target"] = ""
+				}
+				content["Target Type"] = helpers.TypeByResourceID(route.Attachment.ResourceID)
+				content["Target Details"] = details[route.Attachment.ResourceID]
+				state := route.State
+				if output.Settings.UseEmoji {
+					if route.State == "blackhole" {
+						state = "❌ " + state
 					} else {
-						content["Target"] = ""
-					}
-					content["Target Type"] = helpers.TypeByResourceID(route.Attachment.ResourceID)
-					state := route.State
-					if output.Settings.UseEmoji {
-						if route.State == "blackhole" {
-							state = "❌ " + state
-						} else {
-							state = "✅ " + state
-						}
+						state = "✅ " + state
 					}
-					content["State"] = state
-					holder := format.OutputHolder{Contents: content}
-					output.AddHolder(holder)
 				}
+				content["State"] = state
+				holder := format.OutputHolder{Contents: content}
+				output.AddHolder(holder)
 			}
 		}
 	}
+}
+
+// tgwMeshOverview shows the routes of all Transit Gateways in the peering mesh
+// and an overview of the peerings. For the graphical output formats it shows
+// the mesh itself.
+func tgwMeshOverview(awsConfig config.AWSConfig, resultTitle string) {
+	mesh := helpers.GetTransitGatewayMesh(awsConfig.Region, awsConfig.Ec2ClientForRegion)
+	if settings.IsDrawIO() || settings.NewOutputSettings().NeedsFromToColumns() {
+		createTgwMeshDiagram(mesh)
+		return
+	}
+	keys := []string{"Transit Gateway Account", "Transit Gateway", "Region", "Route Table", "CIDR", "Target", "Target Type", "State"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	output.Settings.SortKey = "Route Table"
+	output.Settings.SeparateTables = true
+	addTgwOverviewRoutes(&output, mesh.TransitGateways, helpers.HybridConnectivity{})
+	output.AddToBuffer()
+
+	peeringKeys := []string{"Peering", "State", "Transit Gateway", "Region", "Peer Transit Gateway", "Peer Account", "Peer Region", "Routes To Peer", "Routes Back"}
+	peeringOutput := format.OutputArray{Keys: peeringKeys, Settings: settings.NewOutputSettings()}
+	peeringOutput.Settings.Title = "Transit Gateway peerings"
+	peeringOutput.Settings.SortKey = "Transit Gateway"
+	peeringOutput.Settings.SeparateTables = true
+	for _, gateway := range mesh.TransitGateways {
+		for _, peering := range mesh.PeeringsFor(gateway.ID) {
+			peer := peering.PeerOf(gateway.ID)
+			content := make(map[string]any)
+			content["Peering"] = getNameWithID(peering.AttachmentID)
+			content["State"] = peering.State
+			content["Transit Gateway"] = getNameWithID(gateway.ID)
+			content["Region"] = gateway.Region
+			content["Peer Transit Gateway"] = getNameWithID(peer.TransitGatewayID)
+			content["Peer Account"] = getNameWithID(peer.AccountID)
+			content["Peer Region"] = peer.Region
+			content["Routes To Peer"] = routeCIDRs(gateway.StaticRoutesOverPeering(peering.AttachmentID))
+			if peerGateway, ok := mesh.GetTransitGateway(peer.TransitGatewayID); ok {
+				content["Routes Back"] = routeCIDRs(peerGateway.StaticRoutesOverPeering(peering.AttachmentID))
+			} else {
+				content["Routes Back"] = "Not accessible"
+			}
+			peeringOutput.AddContents(content)
+		}
+	}
+	peeringOutput.AddToBuffer()
+	output.Write()
+}
+
+func routeCIDRs(routes []helpers.TransitGatewayRoute) []string {
+	result := make([]string, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, route.CIDR)
+	}
+	return result
+}
+
+// createTgwMeshDiagram shows the Transit Gateways and their peerings. Each
+// peering is only drawn from the requester side to prevent duplicate lines.
+func createTgwMeshDiagram(mesh helpers.TransitGatewayMesh) {
+	keys := []string{"ID", "Name", "Account", "Region", "Peers"}
+	if settings.IsDrawIO() {
+		keys = append(keys, "Image")
+	}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = "Transit Gateway peering mesh"
+	if settings.IsDrawIO() {
+		drawioheader := drawio.NewHeader("%Name%", "%Image%", "Image")
+		drawioheader.SetHeightAndWidth("78", "78")
+		connection := drawio.NewConnection()
+		connection.From = "Peers"
+		connection.To = "ID"
+		connection.Invert = false
+		connection.Style = drawio.BidirectionalConnectionStyle
+		drawioheader.AddConnection(connection)
+		output.Settings.DrawIOHeader = drawioheader
+	}
+	if output.Settings.NeedsFromToColumns() {
+		output.Settings.AddFromToColumns("ID", "Peers")
+	}
+	nodes := make(map[string]helpers.TransitGatewayPeer)
+	names := make(map[string]string)
+	peers := make(map[string][]string)
+	for _, gateway := range mesh.TransitGateways {
+		nodes[gateway.ID] = helpers.TransitGatewayPeer{TransitGatewayID: gateway.ID, AccountID: gateway.AccountID, Region: gateway.Region}
+		if gateway.Name != "" {
+			names[gateway.ID] = gateway.Name
+		}
+	}
+	for _, peering := range mesh.Peerings {
+		for _, side := range []helpers.TransitGatewayPeer{peering.Requester, peering.Accepter} {
+			if _, ok := nodes[side.TransitGatewayID]; !ok {
+				nodes[side.TransitGatewayID] = side
+			}
+		}
+		peers[peering.Requester.TransitGatewayID] = append(peers[peering.Requester.TransitGatewayID], peering.Accepter.TransitGatewayID)
+	}
+	for id, node := range nodes {
+		content := make(map[string]any)
+		content["ID"] = id
+		name, ok := names[id]
+		if !ok {
+			name = getName(id)
+		}
+		content["Name"] = fmt.Sprintf("%s (%s)", name, node.Region)
+		content["Account"] = getNameWithID(node.AccountID)
+		content["Region"] = node.Region
+		content["Peers"] = unique(peers[id])
+		if settings.IsDrawIO() {
+			content["Image"] = drawio.AWSShape("Network Content Delivery", "Transit Gateway")
+		}
+		output.AddContents(content)
+	}
 	output.Write()
 }
 
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ArjenSchwarz/awstools/config"
+	"github.com/ArjenSchwarz/awstools/helpers"
+	format "github.com/ArjenSchwarz/go-output"
+	"github.com/spf13/cobra"
+)
+
+// tgwverifyCmd represents the tgw verify command
+var tgwverifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify Transit Gateway route tables against a desired state",
+	Long: `Verify the Transit Gateway route tables against a desired state defined in a YAML file.
+
+For each route table in the spec, the associations, propagations, and static routes
+(including blackhole routes) are compared against the live configuration. Every
+difference is reported as a change that needs to be made to the live configuration to
+match the spec: + for something that is missing and - for something that shouldn't be
+there. Attachments can be referenced by their attachment ID or the ID of the attached
+resource. Route tables that aren't in the spec are ignored.
+
+When any drift is found, the command exits with a non-zero exit code.
+
+An example spec:
+
+	route_tables:
+	  - id: tgw-rtb-0123456789abcdef0
+	    name: spokes
+	    associations:
+	      - vpc-0123456789abcdef0
+	    propagations:
+	      - tgw-attach-0123456789abcdef0
+	    static_routes:
+	      - cidr: 0.0.0.0/0
+	        attachment: tgw-attach-0123456789abcdef0
+	      - cidr: 10.99.0.0/16
+	        blackhole: true
+
+Example:
+
+	awstools tgw verify --spec tgw.yaml -o table`,
+	Run: tgwverify,
+}
+
+var tgwverifySpec string
+
+func init() {
+	tgwCmd.AddCommand(tgwverifyCmd)
+	tgwverifyCmd.Flags().StringVar(&tgwverifySpec, "spec", "", "The YAML file containing the desired state of the route tables")
+	_ = tgwverifyCmd.MarkFlagRequired("spec")
+}
+
+func tgwverify(_ *cobra.Command, _ []string) {
+	spec, err := helpers.LoadTransitGatewaySpec(tgwverifySpec)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	awsConfig := config.DefaultAwsConfig(*settings)
+	gateways := helpers.GetAllTransitGateways(awsConfig.Ec2Client())
+	drift := helpers.VerifyTransitGateways(gateways, spec)
+	keys := []string{"Route Table", "Type", "Change", "Value", "Diff"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = fmt.Sprintf("Transit Gateway drift from %s (%d differences)", tgwverifySpec, len(drift))
+	for _, difference := range drift {
+		content := make(map[string]any)
+		content["Route Table"] = getNameWithID(difference.RouteTable)
+		content["Type"] = difference.Type
+		change := difference.Change
+		if output.Settings.UseEmoji {
+			if difference.Change == helpers.TransitGatewayDriftAdd {
+				change = "➕ " + change
+			} else {
+				change = "➖ " + change
+			}
+		}
+		content["Change"] = change
+		content["Value"] = difference.Value
+		content["Diff"] = difference.String()
+		output.AddContents(content)
+	}
+	output.Write()
+	if len(drift) > 0 {
+		os.Exit(1)
+	}
+}
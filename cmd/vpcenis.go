@@ -64,13 +64,13 @@ func enis(_ *cobra.Command, _ []string) {
 }
 
 func printENIs(interfaces []types.NetworkInterface, names map[string]string, resultTitle string, split bool, svc eniAttachmentLookupClient) {
-	keys := []string{"ENI", "Type", "Attachment", "IPs", "VPC", "Subnet"}
+	keys := []string{"ENI", "Type", "Usage", "Attachment", "IPs", "VPC", "Subnet"}
 	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
 	output.Settings.Title = resultTitle
 	output.Settings.SortKey = "Subnet"
 	if split {
 		// unset VPC and subnet
-		output.Keys = []string{"ENI", "Type", "Attachment", "IPs"}
+		output.Keys = []string{"ENI", "Type", "Usage", "Attachment", "IPs"}
 		output.Settings.SeparateTables = true
 		output.Settings.SortKey = "Attachment"
 	}
@@ -88,6 +88,7 @@ func printENIs(interfaces []types.NetworkInterface, names map[string]string, res
 		}
 		content["ENI"] = aws.ToString(netinterface.NetworkInterfaceId)
 		content["Type"] = netinterface.InterfaceType
+		content["Usage"] = helpers.GetENIUsageType(netinterface)
 		content["Attachment"] = getNameAndIDFromMap(getAttachment(netinterface, svc), names)
 		content["IPs"] = iparray
 		content["VPC"] = getNameAndIDFromMap(aws.ToString(netinterface.VpcId), names)
@@ -109,8 +110,10 @@ func splitBySubnet(interfaces []types.NetworkInterface) map[string][]types.Netwo
 // getAttachment resolves the attachment label for a given ENI. For instance
 // ENIs it returns the instance ID directly; for TGW/NAT/VPC-endpoint ENIs it
 // dispatches to the matching paginated helper (T-657 fixed those helpers to
-// walk every page). The svc parameter is the composite client interface so
-// tests can supply a paginating mock without a real *ec2.Client.
+// walk every page). Other service owned ENIs are resolved from the ENI
+// itself through helpers.ClassifyENI. The svc parameter is the composite
+// client interface so tests can supply a paginating mock without a real
+// *ec2.Client.
 func getAttachment(netinterface types.NetworkInterface, svc eniAttachmentLookupClient) string {
 	if netinterface.Attachment != nil && netinterface.Attachment.InstanceId != nil {
 		return *netinterface.Attachment.InstanceId
@@ -132,6 +135,9 @@ func getAttachment(netinterface types.NetworkInterface, svc eniAttachmentLookupC
 		}
 		return ""
 	}
+	if classification, ok := helpers.ClassifyENI(netinterface); ok {
+		return classification.String()
+	}
 	return ""
 }
 
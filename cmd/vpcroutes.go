@@ -6,6 +6,7 @@ import (
 	"github.com/ArjenSchwarz/awstools/config"
 	"github.com/ArjenSchwarz/awstools/helpers"
 	format "github.com/ArjenSchwarz/go-output"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/spf13/cobra"
 )
 
@@ -13,18 +14,39 @@ import (
 var routesCmd = &cobra.Command{
 	Use:   "routes",
 	Short: "Get VPC Routes",
-	Long:  `Get an overview of the routes of all VPCs in the account.`,
-	Run:   routes,
+	Long: `Get an overview of the routes of all VPCs in the account.
+
+Using the --audit flag, the route tables are instead checked for common
+hygiene problems:
+  - blackhole routes, including routes to deleted peering connections or ENIs
+  - routes that overlap with, or are redundant because of, a broader route
+    other than a default route or the local route of the VPC
+  - route tables that aren't associated with any subnet or gateway
+  - subnets that implicitly use the main route table of their VPC
+
+Examples:
+
+	awstools vpc routes --audit -o table`,
+	Run: routes,
 }
 
+var vpcroutesAudit bool
+
 func init() {
 	vpcCmd.AddCommand(routesCmd)
+	routesCmd.Flags().BoolVar(&vpcroutesAudit, "audit", false, "Audit the route tables for hygiene issues instead of listing the routes")
 }
 
 func routes(_ *cobra.Command, _ []string) {
 	awsConfig := config.DefaultAwsConfig(*settings)
-	resultTitle := "VPC Routes for account " + getName(helpers.GetAccountID(awsConfig.StsClient()))
+	accountName := getName(helpers.GetAccountID(awsConfig.StsClient()))
+	resultTitle := "VPC Routes for account " + accountName
 	routes := helpers.GetAllVPCRouteTables(awsConfig.Ec2Client())
+	if vpcroutesAudit {
+		subnets := helpers.GetAllSubnets(awsConfig.Ec2Client())
+		auditRoutes(routes, subnets, "VPC Route Table audit for account "+accountName)
+		return
+	}
 	keys := []string{"AccountID", "Account Name", "ID", "Name", "VPC", "VPC Name", "Subnets", "Routes"}
 	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
 	output.Settings.Title = resultTitle
@@ -120,3 +142,39 @@ func routes(_ *cobra.Command, _ []string) {
 	// }
 	output.Write()
 }
+
+func auditRoutes(routetables []helpers.VPCRouteTable, subnets []types.Subnet, resultTitle string) {
+	keys := []string{"AccountID", "Account Name", "ID", "Name", "VPC", "VPC Name", "Issue", "Destination", "Target", "Subnet", "Details"}
+	output := format.OutputArray{Keys: keys, Settings: settings.NewOutputSettings()}
+	output.Settings.Title = resultTitle
+	output.Settings.SortKey = "ID"
+	for _, finding := range helpers.AuditVPCRouteTables(routetables, subnets) {
+		content := make(map[string]any)
+		content["AccountID"] = finding.RouteTable.Vpc.AccountID
+		content["Account Name"] = getName(finding.RouteTable.Vpc.AccountID)
+		content["ID"] = finding.RouteTable.ID
+		content["Name"] = getName(finding.RouteTable.ID)
+		content["VPC"] = finding.RouteTable.Vpc.ID
+		content["VPC Name"] = getName(finding.RouteTable.Vpc.ID)
+		issue := finding.Issue
+		if output.Settings.UseEmoji {
+			issue = "⚠️ " + issue
+		}
+		content["Issue"] = issue
+		content["Destination"] = finding.Destination
+		if finding.Target != "" {
+			content["Target"] = getNameWithID(finding.Target)
+		} else {
+			content["Target"] = ""
+		}
+		if finding.Subnet != "" {
+			content["Subnet"] = getNameWithID(finding.Subnet)
+		} else {
+			content["Subnet"] = ""
+		}
+		content["Details"] = finding.Details
+		holder := format.OutputHolder{Contents: content}
+		output.AddHolder(holder)
+	}
+	output.Write()
+}
@@ -8,9 +8,12 @@ import (
 	external "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/appmesh"
 	"github.com/aws/aws-sdk-go-v2/service/cloudformation"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
@@ -38,8 +41,14 @@ func resolveProfile(config Config) string {
 
 // DefaultAwsConfig loads default AWS Config
 func DefaultAwsConfig(config Config) AWSConfig {
+	return AwsConfigForProfile(config, resolveProfile(config))
+}
+
+// AwsConfigForProfile loads the AWS Config for the provided profile, using
+// the default credential chain if the profile is empty. The region is still
+// taken from the configuration.
+func AwsConfigForProfile(config Config, profile string) AWSConfig {
 	awsConfig := AWSConfig{}
-	profile := resolveProfile(config)
 	if profile != "" {
 		awsConfig.ProfileName = profile
 		cfg, err := external.LoadDefaultConfig(context.TODO(), external.WithSharedConfigProfile(profile))
@@ -120,6 +129,23 @@ func (config *AWSConfig) Ec2Client() *ec2.Client {
 	return ec2.NewFromConfig(config.Config)
 }
 
+// Ec2ClientForRegion returns an ec2 Client for the provided region
+func (config *AWSConfig) Ec2ClientForRegion(region string) *ec2.Client {
+	return ec2.NewFromConfig(config.Config, func(o *ec2.Options) {
+		o.Region = region
+	})
+}
+
+// DirectConnectClient returns a Direct Connect Client
+func (config *AWSConfig) DirectConnectClient() *directconnect.Client {
+	return directconnect.NewFromConfig(config.Config)
+}
+
+// RAMClient returns a Resource Access Manager Client
+func (config *AWSConfig) RAMClient() *ram.Client {
+	return ram.NewFromConfig(config.Config)
+}
+
 // RdsClient returns an rds Client
 func (config *AWSConfig) RdsClient() *rds.Client {
 	return rds.NewFromConfig(config.Config)
@@ -140,6 +166,11 @@ func (config *AWSConfig) SsoClient() *ssoadmin.Client {
 	return ssoadmin.NewFromConfig(config.Config)
 }
 
+// IdentityStoreClient returns an Identity Store Client
+func (config *AWSConfig) IdentityStoreClient() *identitystore.Client {
+	return identitystore.NewFromConfig(config.Config)
+}
+
 // S3Client returns an S3 Client
 func (config *AWSConfig) S3Client() *s3.Client {
 	return s3.NewFromConfig(config.Config)
@@ -320,12 +320,15 @@ func getAllVpcPeers(svc ec2.DescribeVpcPeeringConnectionsAPIClient) []VpcPeering
 }
 
 // VPCRouteTable contains the relevant information for a Route Table
+// Default is set for the main route table of the VPC, and Gateways holds the
+// IDs of any gateways (edge associations) the route table is associated with.
 type VPCRouteTable struct {
-	Vpc     VPCHolder
-	ID      string
-	Routes  []VPCRoute
-	Subnets []string
-	Default bool
+	Vpc      VPCHolder
+	ID       string
+	Routes   []VPCRoute
+	Subnets  []string
+	Gateways []string
+	Default  bool
 }
 
 // VPCRoute represents a Route object
@@ -356,17 +359,27 @@ func getAllVPCRouteTables(svc ec2.DescribeRouteTablesAPIClient) []VPCRouteTable
 		}
 		for _, routetable := range page.RouteTables {
 			var subnets []string
+			var gateways []string
+			isMain := false
 			for _, assocs := range routetable.Associations {
 				if assocs.SubnetId != nil {
 					subnets = append(subnets, *assocs.SubnetId)
 				}
+				if assocs.GatewayId != nil {
+					gateways = append(gateways, *assocs.GatewayId)
+				}
+				if aws.ToBool(assocs.Main) {
+					isMain = true
+				}
 			}
 			table := VPCRouteTable{
 				Vpc: VPCHolder{ID: aws.ToString(routetable.VpcId),
 					AccountID: aws.ToString(routetable.OwnerId)},
-				ID:      aws.ToString(routetable.RouteTableId),
-				Routes:  parseVPCRoutes(routetable.Routes),
-				Subnets: subnets,
+				ID:       aws.ToString(routetable.RouteTableId),
+				Routes:   parseVPCRoutes(routetable.Routes),
+				Subnets:  subnets,
+				Gateways: gateways,
+				Default:  isMain,
 			}
 			result = append(result, table)
 		}
@@ -417,10 +430,13 @@ type TransitGateway struct {
 	ID          string
 	AccountID   string
 	Name        string
+	Region      string
 	RouteTables map[string]TransitGatewayRouteTable
 }
 
-// TransitGatewayRouteTable is a struct for managing Transit Gateway route table objects
+// TransitGatewayRouteTable is a struct for managing Transit Gateway route table objects.
+// SourceAttachments are the attachments associated with the route table, while
+// DestinationAttachments are the attachments propagating their routes into it.
 type TransitGatewayRouteTable struct {
 	ID                     string
 	Name                   string
@@ -448,11 +464,12 @@ type TransitGatewayAttachment struct {
 // tgwInventoryAPIClient bundles the EC2 APIClient interfaces needed by the
 // TGW inventory helpers. *ec2.Client satisfies this composite interface so
 // production callers are unaffected; tests can pass a mock that implements
-// all four methods.
+// all five methods.
 type tgwInventoryAPIClient interface {
 	ec2.DescribeTransitGatewaysAPIClient
 	ec2.DescribeTransitGatewayRouteTablesAPIClient
 	ec2.GetTransitGatewayRouteTableAssociationsAPIClient
+	ec2.GetTransitGatewayRouteTablePropagationsAPIClient
 	SearchTransitGatewayRoutes(ctx context.Context, params *ec2.SearchTransitGatewayRoutesInput, optFns ...func(*ec2.Options)) (*ec2.SearchTransitGatewayRoutesOutput, error)
 }
 
@@ -480,6 +497,7 @@ func getAllTransitGateways(svc tgwInventoryAPIClient) []TransitGateway {
 				ID:          tgwID,
 				AccountID:   aws.ToString(tgw.OwnerId),
 				Name:        getNameFromTags(tgw.Tags),
+				Region:      regionFromArn(aws.ToString(tgw.TransitGatewayArn)),
 				RouteTables: getRouteTablesForTransitGateway(tgwID, svc),
 			}
 			result = append(result, tgwobject)
@@ -524,6 +542,7 @@ func getRouteTablesForTransitGateway(tgwID string, svc tgwInventoryAPIClient) ma
 	for _, routetable := range result {
 		routetable.Routes = append(getActiveRoutesForTransitGatewayRouteTable(routetable.ID, svc), getBlackholeRoutesForTransitGatewayRouteTable(routetable.ID, svc)...)
 		routetable.SourceAttachments = getSourceAttachmentsForTransitGatewayRouteTable(routetable.ID, svc)
+		routetable.DestinationAttachments = getDestinationAttachmentsForTransitGatewayRouteTable(routetable.ID, svc)
 		result[routetable.ID] = routetable
 	}
 	return result
@@ -561,6 +580,41 @@ func getSourceAttachmentsForTransitGatewayRouteTable(routetableID string, svc tg
 	return result
 }
 
+// GetDestinationAttachmentsForTransitGatewayRouteTable returns all the attachments that propagate their routes into a Transit Gateway route table
+func GetDestinationAttachmentsForTransitGatewayRouteTable(routetableID string, svc *ec2.Client) []TransitGatewayAttachment {
+	return getDestinationAttachmentsForTransitGatewayRouteTable(routetableID, svc)
+}
+
+// getDestinationAttachmentsForTransitGatewayRouteTable implements
+// GetDestinationAttachmentsForTransitGatewayRouteTable against the narrow
+// tgwInventoryAPIClient interface. It walks
+// NewGetTransitGatewayRouteTablePropagationsPaginator.
+func getDestinationAttachmentsForTransitGatewayRouteTable(routetableID string, svc tgwInventoryAPIClient) []TransitGatewayAttachment {
+	var result []TransitGatewayAttachment
+	params := &ec2.GetTransitGatewayRouteTablePropagationsInput{
+		TransitGatewayRouteTableId: &routetableID,
+	}
+	paginator := ec2.NewGetTransitGatewayRouteTablePropagationsPaginator(svc, params)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, propagation := range page.TransitGatewayRouteTablePropagations {
+			if propagation.State != types.TransitGatewayPropagationStateEnabled {
+				continue
+			}
+			tgwattachment := TransitGatewayAttachment{
+				ID:           aws.ToString(propagation.TransitGatewayAttachmentId),
+				ResourceID:   aws.ToString(propagation.ResourceId),
+				ResourceType: string(propagation.ResourceType),
+			}
+			result = append(result, tgwattachment)
+		}
+	}
+	return result
+}
+
 // tgwSearchRoutesMaxResults is the hard cap SearchTransitGatewayRoutes will
 // return in a single call. The AWS API does not support NextToken for this
 // operation, so the only way to retrieve more is to narrow the filter set.
@@ -1095,6 +1149,12 @@ func retrieveVPCData(svc *ec2.Client) []types.Vpc {
 	return result
 }
 
+// GetAllSubnets returns every subnet in the account and region, walking all
+// pages of DescribeSubnets.
+func GetAllSubnets(svc *ec2.Client) []types.Subnet {
+	return retrieveSubnetData(svc)
+}
+
 // retrieveSubnetData fetches all subnets using DescribeSubnets API
 func retrieveSubnetData(svc *ec2.Client) []types.Subnet {
 	var result []types.Subnet
@@ -1445,28 +1505,35 @@ func getENIUsageTypeOptimized(eni types.NetworkInterface, cache *ENILookupCache)
 		}
 	}
 
+	// Handle the interface types that are resolved through the cache
+	switch eni.InterfaceType {
+	case types.NetworkInterfaceTypeTransitGateway:
+		return "Transit Gateway"
+	case types.NetworkInterfaceTypeNatGateway:
+		return "NAT Gateway"
+	case types.NetworkInterfaceTypeVpcEndpoint:
+		return vpcEndpointType
+	}
+
+	// Handle services that can be recognised from the ENI itself
+	if classification, ok := ClassifyENI(eni); ok {
+		return classification.Service
+	}
+
 	// Handle EC2 instances
 	if eni.Attachment != nil && eni.Attachment.InstanceId != nil {
 		return "EC2 Instance"
 	}
 
-	// Handle specific interface types (if not 'interface')
+	// Handle remaining specific interface types (if not 'interface')
 	if eni.InterfaceType != interfaceType {
 		switch eni.InterfaceType {
-		case types.NetworkInterfaceTypeTransitGateway:
-			return "Transit Gateway"
-		case types.NetworkInterfaceTypeNatGateway:
-			return "NAT Gateway"
-		case types.NetworkInterfaceTypeVpcEndpoint:
-			return vpcEndpointType
-		case types.NetworkInterfaceTypeLambda:
-			return lambdaFunctionType
 		case "quicksight":
 			return "QuickSight"
 		case "network_load_balancer":
-			return "Network Load Balancer"
+			return networkLoadBalancerType
 		case "gateway_load_balancer":
-			return "Gateway Load Balancer"
+			return gatewayLoadBalancerType
 		default:
 			return awsServiceType
 		}
@@ -1479,7 +1546,7 @@ func getENIUsageTypeOptimized(eni types.NetworkInterface, cache *ENILookupCache)
 			return "Load Balancer"
 		}
 		if strings.Contains(desc, "rds") {
-			return "RDS Database"
+			return rdsDatabaseType
 		}
 		if strings.Contains(desc, "lambda") {
 			return lambdaFunctionType
@@ -1488,19 +1555,19 @@ func getENIUsageTypeOptimized(eni types.NetworkInterface, cache *ENILookupCache)
 			return "VPC Service"
 		}
 		if strings.Contains(desc, "elasticache") {
-			return "ElastiCache"
+			return elastiCacheType
 		}
 		if strings.Contains(desc, "efs") {
-			return "EFS Mount Target"
+			return efsMountTargetType
 		}
 		if strings.Contains(desc, "redshift") {
-			return "Redshift Cluster"
+			return redshiftClusterType
 		}
 		if strings.Contains(desc, "apigateway") {
 			return "API Gateway"
 		}
 		if strings.Contains(desc, "codebuild") {
-			return "CodeBuild"
+			return codeBuildType
 		}
 		// If description contains service keywords, it's likely a service
 		return awsServiceType
@@ -1538,7 +1605,15 @@ func getENIAttachmentDetailsOptimized(eni types.NetworkInterface, cache *ENILook
 		return instanceID
 	}
 
-	// Priority 3: Handle specific interface types (if not 'interface' and not empty)
+	// Priority 3: Handle services that can be recognised from the ENI itself,
+	// unattributed service ENIs fall through to their description
+	if eni.InterfaceType != types.NetworkInterfaceTypeTransitGateway && eni.InterfaceType != types.NetworkInterfaceTypeNatGateway {
+		if classification, ok := ClassifyENI(eni); ok && classification.Service != awsServiceType {
+			return classification.String()
+		}
+	}
+
+	// Priority 4: Handle specific interface types (if not 'interface' and not empty)
 	if eni.InterfaceType != "" && eni.InterfaceType != interfaceType {
 		switch eni.InterfaceType {
 		case types.NetworkInterfaceTypeTransitGateway:
@@ -1571,7 +1646,7 @@ func getENIAttachmentDetailsOptimized(eni types.NetworkInterface, cache *ENILook
 		}
 	}
 
-	// Priority 4: Check description for service keywords (following JS script logic)
+	// Priority 5: Check description for service keywords (following JS script logic)
 	if eni.Description != nil {
 		desc := strings.ToLower(*eni.Description)
 		if strings.Contains(desc, "elb") || strings.Contains(desc, "rds") ||
@@ -1582,7 +1657,7 @@ func getENIAttachmentDetailsOptimized(eni types.NetworkInterface, cache *ENILook
 		return *eni.Description
 	}
 
-	// Priority 5: Unattached or unknown
+	// Priority 6: Unattached or unknown
 	if eni.Attachment == nil {
 		return "Unattached"
 	}
@@ -2136,6 +2211,11 @@ func getResourceNameAndID(eni types.NetworkInterface, cache *ENILookupCache) (st
 		}
 	}
 
+	// Handle service owned ENIs that identify their resource
+	if classification, ok := ClassifyENI(eni); ok && classification.ResourceID != "" {
+		return attachmentDetails, classification.ResourceID
+	}
+
 	// Default to attachment details
 	return attachmentDetails, ""
 }
@@ -0,0 +1,183 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// Service types for ENIs that are created and managed by AWS services
+const (
+	eksControlPlaneType         = "EKS Control Plane"
+	eksNodeType                 = "EKS Node"
+	eksPodType                  = "EKS Pod"
+	ecsTaskType                 = "ECS Task"
+	rdsDatabaseType             = "RDS Database"
+	classicLoadBalancerType     = "Classic Load Balancer"
+	applicationLoadBalancerType = "Application Load Balancer"
+	networkLoadBalancerType     = "Network Load Balancer"
+	gatewayLoadBalancerType     = "Gateway Load Balancer"
+	efsMountTargetType          = "EFS Mount Target"
+	fsxFileSystemType           = "FSx File System"
+	directoryServiceType        = "Directory Service"
+	apiGatewayVPCLinkType       = "API Gateway VPC Link"
+	resolverEndpointType        = "Route 53 Resolver Endpoint"
+	elastiCacheType             = "ElastiCache"
+	redshiftClusterType         = "Redshift Cluster"
+	codeBuildType               = "CodeBuild"
+)
+
+// Tags the EKS VPC CNI and VPC resource controller place on the ENIs they manage
+const (
+	eksCNIClusterTag    = "cluster.k8s.amazonaws.com/name"
+	eksCNIInstanceTag   = "node.k8s.amazonaws.com/instance_id"
+	eksBranchClusterTag = "vpcresources.k8s.aws/cluster-name"
+)
+
+var (
+	lambdaENIPattern       = regexp.MustCompile(`^AWS Lambda VPC ENI-(.+?)(-[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})?$`)
+	eksControlPlanePattern = regexp.MustCompile(`^Amazon EKS (.+)$`)
+	ecsAttachmentPattern   = regexp.MustCompile(`^arn:aws[a-z-]*:ecs:[^:]+:[0-9]+:attachment/(.+)$`)
+	elbv2ENIPattern        = regexp.MustCompile(`^ELB (app|net|gwy)/([^/]+)/([0-9a-f]+)$`)
+	classicELBENIPattern   = regexp.MustCompile(`^ELB ([^/\s]+)$`)
+	efsENIPattern          = regexp.MustCompile(`^EFS mount target for (fs-[0-9a-f]+) \((fsmt-[0-9a-f]+)\)$`)
+	fsxENIPattern          = regexp.MustCompile(`^ENI for Amazon FSx .*\b(fs-[0-9a-f]{8,17})\b`)
+	directoryENIPattern    = regexp.MustCompile(`^AWS created network interface for directory (d-[0-9a-f]+)$`)
+	vpcLinkENIPattern      = regexp.MustCompile(`^VPC Link ENI for (vpclink-[0-9a-z]+|[0-9a-z]{6,10})$`)
+	resolverENIPattern     = regexp.MustCompile(`^Route 53 Resolver: (rslvr-(?:in|out)-[0-9a-f]+)`)
+	elastiCacheENIPattern  = regexp.MustCompile(`^ElastiCache (.+)$`)
+)
+
+// ENIClassification describes the AWS service and resource that own an ENI.
+// ResourceID and ResourceName are filled in as far as they can be derived
+// from the ENI itself. RDS ENIs don't reference their database instance in
+// any way, so for those only the Service is known.
+type ENIClassification struct {
+	Service      string
+	ResourceID   string
+	ResourceName string
+}
+
+// String returns the owning resource in the "name (id)" format used by the
+// other ENI attachment details
+func (classification ENIClassification) String() string {
+	switch {
+	case classification.ResourceName != "" && classification.ResourceID != "":
+		return classification.ResourceName + " (" + classification.ResourceID + ")"
+	case classification.ResourceID != "":
+		return classification.ResourceID
+	case classification.ResourceName != "":
+		return classification.ResourceName
+	}
+	return classification.Service
+}
+
+// ClassifyENI determines which AWS service owns an ENI and, where possible,
+// which resource of that service it belongs to. This relies on the interface
+// type, the description and the tags the services put on the ENIs they
+// create, so no additional API calls are needed. The boolean return value
+// is false if the ENI couldn't be attributed to a service.
+func ClassifyENI(eni types.NetworkInterface) (ENIClassification, bool) {
+	description := aws.ToString(eni.Description)
+	tags := tagMap(eni.TagSet)
+
+	if eni.InterfaceType == types.NetworkInterfaceTypeLambda || strings.HasPrefix(description, "AWS Lambda VPC ENI") {
+		classification := ENIClassification{Service: lambdaFunctionType}
+		if matches := lambdaENIPattern.FindStringSubmatch(description); matches != nil {
+			classification.ResourceName = matches[1]
+		}
+		return classification, true
+	}
+	if cluster, ok := tags[eksBranchClusterTag]; ok || eni.InterfaceType == types.NetworkInterfaceTypeBranch {
+		return ENIClassification{Service: eksPodType, ResourceName: cluster}, true
+	}
+	if cluster, ok := tags[eksCNIClusterTag]; ok || strings.HasPrefix(description, "aws-K8S-") {
+		classification := ENIClassification{
+			Service:      eksNodeType,
+			ResourceID:   tags[eksCNIInstanceTag],
+			ResourceName: cluster,
+		}
+		if classification.ResourceID == "" && eni.Attachment != nil {
+			classification.ResourceID = aws.ToString(eni.Attachment.InstanceId)
+		}
+		if classification.ResourceID == "" {
+			classification.ResourceID = strings.TrimPrefix(description, "aws-K8S-")
+		}
+		return classification, true
+	}
+	if matches := eksControlPlanePattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: eksControlPlaneType, ResourceName: matches[1]}, true
+	}
+	if matches := ecsAttachmentPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: ecsTaskType, ResourceID: matches[1]}, true
+	}
+	if description == "RDSNetworkInterface" || aws.ToString(eni.RequesterId) == "amazon-rds" {
+		// Neither the description nor the tags identify the database instance
+		return ENIClassification{Service: rdsDatabaseType}, true
+	}
+	if matches := elbv2ENIPattern.FindStringSubmatch(description); matches != nil {
+		service := applicationLoadBalancerType
+		switch matches[1] {
+		case "net":
+			service = networkLoadBalancerType
+		case "gwy":
+			service = gatewayLoadBalancerType
+		}
+		return ENIClassification{
+			Service:      service,
+			ResourceID:   matches[1] + "/" + matches[2] + "/" + matches[3],
+			ResourceName: matches[2],
+		}, true
+	}
+	if matches := classicELBENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: classicLoadBalancerType, ResourceName: matches[1]}, true
+	}
+	if matches := efsENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: efsMountTargetType, ResourceID: matches[2], ResourceName: matches[1]}, true
+	}
+	if matches := fsxENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: fsxFileSystemType, ResourceID: matches[1]}, true
+	}
+	if matches := directoryENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: directoryServiceType, ResourceID: matches[1]}, true
+	}
+	if matches := vpcLinkENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: apiGatewayVPCLinkType, ResourceID: matches[1]}, true
+	}
+	if matches := resolverENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: resolverEndpointType, ResourceID: matches[1]}, true
+	}
+	if matches := elastiCacheENIPattern.FindStringSubmatch(description); matches != nil {
+		return ENIClassification{Service: elastiCacheType, ResourceName: matches[1]}, true
+	}
+	if description == "RedshiftNetworkInterface" {
+		return ENIClassification{Service: redshiftClusterType}, true
+	}
+	if strings.HasPrefix(description, "CodeBuild") {
+		return ENIClassification{Service: codeBuildType}, true
+	}
+	if aws.ToBool(eni.RequesterManaged) {
+		// Managed by a service we don't know how to attribute yet, the
+		// requester ID at least tells which service or account created it
+		return ENIClassification{Service: awsServiceType, ResourceName: aws.ToString(eni.RequesterId)}, true
+	}
+	return ENIClassification{}, false
+}
+
+// tagMap converts EC2 tags into a map for easier lookups
+func tagMap(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
+
+// GetENIUsageType returns the general category of what the ENI is used for,
+// based purely on the ENI itself. Use this when no ENILookupCache has been
+// built, such as when listing ENIs without analysing their IP usage.
+func GetENIUsageType(eni types.NetworkInterface) string {
+	return getENIUsageTypeOptimized(eni, &ENILookupCache{})
+}
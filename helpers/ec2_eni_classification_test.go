@@ -0,0 +1,254 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+func TestClassifyENI(t *testing.T) {
+	tests := []struct {
+		name     string
+		eni      types.NetworkInterface
+		expected ENIClassification
+	}{
+		{
+			name: "Lambda function",
+			eni: types.NetworkInterface{
+				InterfaceType: types.NetworkInterfaceTypeLambda,
+				Description:   aws.String("AWS Lambda VPC ENI-my-function-3f2b8c1e-1d2a-4b5c-9d8e-0f1a2b3c4d5e"),
+			},
+			expected: ENIClassification{Service: lambdaFunctionType, ResourceName: "my-function"},
+		},
+		{
+			name: "EKS pod branch ENI",
+			eni: types.NetworkInterface{
+				InterfaceType: types.NetworkInterfaceTypeBranch,
+				Description:   aws.String("aws-k8s-branch-eni"),
+				TagSet:        []types.Tag{{Key: aws.String(eksBranchClusterTag), Value: aws.String("prod")}},
+			},
+			expected: ENIClassification{Service: eksPodType, ResourceName: "prod"},
+		},
+		{
+			name: "EKS node secondary ENI",
+			eni: types.NetworkInterface{
+				InterfaceType: types.NetworkInterfaceTypeInterface,
+				Description:   aws.String("aws-K8S-i-0123456789abcdef0"),
+				Attachment:    &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-0123456789abcdef0")},
+				TagSet:        []types.Tag{{Key: aws.String(eksCNIClusterTag), Value: aws.String("prod")}},
+			},
+			expected: ENIClassification{Service: eksNodeType, ResourceID: "i-0123456789abcdef0", ResourceName: "prod"},
+		},
+		{
+			name: "EKS control plane",
+			eni: types.NetworkInterface{
+				InterfaceType: types.NetworkInterfaceTypeInterface,
+				Description:   aws.String("Amazon EKS prod"),
+			},
+			expected: ENIClassification{Service: eksControlPlaneType, ResourceName: "prod"},
+		},
+		{
+			name: "ECS task",
+			eni: types.NetworkInterface{
+				InterfaceType: types.NetworkInterfaceTypeInterface,
+				Description:   aws.String("arn:aws:ecs:ap-southeast-2:123456789012:attachment/6a9c1f6e-2b3d-4c5e-8f9a-0b1c2d3e4f5a"),
+			},
+			expected: ENIClassification{Service: ecsTaskType, ResourceID: "6a9c1f6e-2b3d-4c5e-8f9a-0b1c2d3e4f5a"},
+		},
+		{
+			name: "RDS database",
+			eni: types.NetworkInterface{
+				Description: aws.String("RDSNetworkInterface"),
+				RequesterId: aws.String("amazon-rds"),
+			},
+			expected: ENIClassification{Service: rdsDatabaseType},
+		},
+		{
+			name: "Application Load Balancer",
+			eni: types.NetworkInterface{
+				Description: aws.String("ELB app/my-alb/50dc6c495c0c9188"),
+			},
+			expected: ENIClassification{Service: applicationLoadBalancerType, ResourceID: "app/my-alb/50dc6c495c0c9188", ResourceName: "my-alb"},
+		},
+		{
+			name: "Network Load Balancer",
+			eni: types.NetworkInterface{
+				InterfaceType: "network_load_balancer",
+				Description:   aws.String("ELB net/my-nlb/50dc6c495c0c9188"),
+			},
+			expected: ENIClassification{Service: networkLoadBalancerType, ResourceID: "net/my-nlb/50dc6c495c0c9188", ResourceName: "my-nlb"},
+		},
+		{
+			name: "Classic Load Balancer",
+			eni: types.NetworkInterface{
+				Description: aws.String("ELB my-classic-lb"),
+			},
+			expected: ENIClassification{Service: classicLoadBalancerType, ResourceName: "my-classic-lb"},
+		},
+		{
+			name: "EFS mount target",
+			eni: types.NetworkInterface{
+				Description: aws.String("EFS mount target for fs-12345678 (fsmt-87654321)"),
+			},
+			expected: ENIClassification{Service: efsMountTargetType, ResourceID: "fsmt-87654321", ResourceName: "fs-12345678"},
+		},
+		{
+			name: "FSx file system",
+			eni: types.NetworkInterface{
+				Description: aws.String("ENI for Amazon FSx file system fs-0123456789abcdef0"),
+			},
+			expected: ENIClassification{Service: fsxFileSystemType, ResourceID: "fs-0123456789abcdef0"},
+		},
+		{
+			name: "Directory Service",
+			eni: types.NetworkInterface{
+				Description: aws.String("AWS created network interface for directory d-9067123456"),
+			},
+			expected: ENIClassification{Service: directoryServiceType, ResourceID: "d-9067123456"},
+		},
+		{
+			name: "API Gateway VPC link",
+			eni: types.NetworkInterface{
+				Description: aws.String("VPC Link ENI for abc123"),
+			},
+			expected: ENIClassification{Service: apiGatewayVPCLinkType, ResourceID: "abc123"},
+		},
+		{
+			name: "API Gateway VPC link with vpclink ID",
+			eni: types.NetworkInterface{
+				Description: aws.String("VPC Link ENI for vpclink-0a1b2c"),
+			},
+			expected: ENIClassification{Service: apiGatewayVPCLinkType, ResourceID: "vpclink-0a1b2c"},
+		},
+		{
+			name: "Route 53 Resolver endpoint",
+			eni: types.NetworkInterface{
+				Description: aws.String("Route 53 Resolver: rslvr-in-0123456789abcdef0:rni-0123456789abcdef0"),
+			},
+			expected: ENIClassification{Service: resolverEndpointType, ResourceID: "rslvr-in-0123456789abcdef0"},
+		},
+		{
+			name: "Unknown requester-managed ENI",
+			eni: types.NetworkInterface{
+				Description:      aws.String("Something new"),
+				RequesterManaged: aws.Bool(true),
+				RequesterId:      aws.String("123456789012"),
+			},
+			expected: ENIClassification{Service: awsServiceType, ResourceName: "123456789012"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ClassifyENI(tt.eni)
+			if !ok {
+				t.Fatalf("ClassifyENI() did not classify the ENI")
+			}
+			if got != tt.expected {
+				t.Errorf("ClassifyENI() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassifyENI_UserManaged(t *testing.T) {
+	eni := types.NetworkInterface{
+		InterfaceType: types.NetworkInterfaceTypeInterface,
+		Attachment:    &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-0123456789abcdef0")},
+	}
+	if got, ok := ClassifyENI(eni); ok {
+		t.Errorf("ClassifyENI() classified a plain instance ENI as %+v", got)
+	}
+}
+
+func TestClassifyENI_LooseDescriptions(t *testing.T) {
+	descriptions := []string{
+		"Interface for my fsx migration test",
+		"Manually created for the vpc link testing",
+	}
+	for _, description := range descriptions {
+		eni := types.NetworkInterface{Description: aws.String(description)}
+		if got, ok := ClassifyENI(eni); ok {
+			t.Errorf("ClassifyENI() classified %q as %+v", description, got)
+		}
+	}
+}
+
+func TestENIClassification_String(t *testing.T) {
+	tests := []struct {
+		classification ENIClassification
+		expected       string
+	}{
+		{ENIClassification{Service: efsMountTargetType, ResourceID: "fsmt-1", ResourceName: "fs-1"}, "fs-1 (fsmt-1)"},
+		{ENIClassification{Service: directoryServiceType, ResourceID: "d-1"}, "d-1"},
+		{ENIClassification{Service: lambdaFunctionType, ResourceName: "my-function"}, "my-function"},
+		{ENIClassification{Service: rdsDatabaseType}, rdsDatabaseType},
+	}
+	for _, tt := range tests {
+		if got := tt.classification.String(); got != tt.expected {
+			t.Errorf("String() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestGetENIUsageType_ServiceENIs(t *testing.T) {
+	tests := []struct {
+		name     string
+		eni      types.NetworkInterface
+		expected string
+	}{
+		{
+			name:     "Transit gateway keeps its own type",
+			eni:      types.NetworkInterface{InterfaceType: types.NetworkInterfaceTypeTransitGateway, RequesterManaged: aws.Bool(true)},
+			expected: "Transit Gateway",
+		},
+		{
+			name: "EKS node takes precedence over the EC2 instance",
+			eni: types.NetworkInterface{
+				Description: aws.String("aws-K8S-i-0123456789abcdef0"),
+				Attachment:  &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-0123456789abcdef0")},
+			},
+			expected: eksNodeType,
+		},
+		{
+			name:     "Plain EC2 instance",
+			eni:      types.NetworkInterface{InterfaceType: interfaceType, Attachment: &types.NetworkInterfaceAttachment{InstanceId: aws.String("i-0123456789abcdef0")}},
+			expected: "EC2 Instance",
+		},
+		{
+			name:     "Gateway load balancer by interface type only",
+			eni:      types.NetworkInterface{InterfaceType: "gateway_load_balancer"},
+			expected: gatewayLoadBalancerType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetENIUsageType(tt.eni); got != tt.expected {
+				t.Errorf("GetENIUsageType() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetResourceNameAndID_ServiceENI(t *testing.T) {
+	eni := types.NetworkInterface{
+		NetworkInterfaceId: aws.String("eni-12345678"),
+		Description:        aws.String("EFS mount target for fs-12345678 (fsmt-87654321)"),
+		RequesterManaged:   aws.Bool(true),
+		InterfaceType:      interfaceType,
+	}
+	cache := &ENILookupCache{
+		EndpointsByENI:   make(map[string]*types.VpcEndpoint),
+		NATGatewaysByENI: make(map[string]*types.NatGateway),
+	}
+
+	name, id := getResourceNameAndID(eni, cache)
+
+	if name != "fs-12345678 (fsmt-87654321)" {
+		t.Errorf("getResourceNameAndID() name = %q, want %q", name, "fs-12345678 (fsmt-87654321)")
+	}
+	if id != "fsmt-87654321" {
+		t.Errorf("getResourceNameAndID() id = %q, want %q", id, "fsmt-87654321")
+	}
+}
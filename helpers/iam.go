@@ -34,6 +34,8 @@ type IAMClient interface {
 	GetGroup(ctx context.Context, params *iam.GetGroupInput, optFns ...func(*iam.Options)) (*iam.GetGroupOutput, error)
 	GetPolicy(ctx context.Context, params *iam.GetPolicyInput, optFns ...func(*iam.Options)) (*iam.GetPolicyOutput, error)
 	GetPolicyVersion(ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error)
+	GetRole(ctx context.Context, params *iam.GetRoleInput, optFns ...func(*iam.Options)) (*iam.GetRoleOutput, error)
+	GetUser(ctx context.Context, params *iam.GetUserInput, optFns ...func(*iam.Options)) (*iam.GetUserOutput, error)
 	GetRolePolicy(ctx context.Context, params *iam.GetRolePolicyInput, optFns ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error)
 	GetAccountSummary(ctx context.Context, params *iam.GetAccountSummaryInput, optFns ...func(*iam.Options)) (*iam.GetAccountSummaryOutput, error)
 	GetAccessKeyLastUsed(ctx context.Context, params *iam.GetAccessKeyLastUsedInput, optFns ...func(*iam.Options)) (*iam.GetAccessKeyLastUsedOutput, error)
@@ -347,24 +349,28 @@ func GetAccountAlias(svc *iam.Client, stsSvc *sts.Client) map[string]string {
 }
 
 func getAttachedPolicy(policyArn *string, svc IAMClient) string {
-	params := &iam.GetPolicyInput{
-		PolicyArn: policyArn,
-	}
-	resp, err := svc.GetPolicy(context.TODO(), params)
+	policyDocument, err := getManagedPolicyDocument(aws.ToString(policyArn), svc)
 	if err != nil {
 		panic(err)
 	}
-	params2 := &iam.GetPolicyVersionInput{
-		PolicyArn: policyArn,                    // Required
-		VersionId: resp.Policy.DefaultVersionId, // Required
-	}
-	resp2, err := svc.GetPolicyVersion(context.TODO(), params2)
+	return policyDocument
+}
+
+// getManagedPolicyDocument returns the URL-decoded document of the default
+// version of the managed policy
+func getManagedPolicyDocument(policyArn string, svc IAMClient) (string, error) {
+	resp, err := svc.GetPolicy(context.TODO(), &iam.GetPolicyInput{
+		PolicyArn: aws.String(policyArn),
+	})
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	policyDocument, err := url.QueryUnescape(*resp2.PolicyVersion.Document)
+	resp2, err := svc.GetPolicyVersion(context.TODO(), &iam.GetPolicyVersionInput{
+		PolicyArn: aws.String(policyArn),
+		VersionId: resp.Policy.DefaultVersionId,
+	})
 	if err != nil {
-		panic(err)
+		return "", err
 	}
-	return policyDocument
+	return url.QueryUnescape(aws.ToString(resp2.PolicyVersion.Document))
 }
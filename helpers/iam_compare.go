@@ -0,0 +1,205 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IAMGrant is a single effect, action, and resource combination taken from
+// a policy statement, together with the statements it was found in
+type IAMGrant struct {
+	Effect    string
+	Action    string
+	Resource  string
+	Condition string
+	Sources   []IAMStatementMatch
+}
+
+// key returns the value used to compare grants. Actions are case-insensitive
+// in IAM, resources aren't.
+func (grant IAMGrant) key() string {
+	return strings.Join([]string{strings.ToLower(grant.Effect), strings.ToLower(grant.Action), grant.Resource, grant.Condition}, "|")
+}
+
+// IAMPolicyComparison is the difference between the grants of two principals
+type IAMPolicyComparison struct {
+	First        IAMPrincipalPolicies
+	Second       IAMPrincipalPolicies
+	OnlyInFirst  []IAMGrant
+	OnlyInSecond []IAMGrant
+	SharedCount  int
+}
+
+// NormalizeIAMGrants splits the statements of all of the principal's
+// policies into one grant per action and resource. Identical grants from
+// different statements are merged. The permissions boundary isn't included.
+func NormalizeIAMGrants(principal IAMPrincipalPolicies) []IAMGrant {
+	grants := make(map[string]*IAMGrant)
+	var order []string
+	for _, policy := range principal.Policies {
+		for index, statement := range policy.Statement {
+			match := IAMStatementMatch{PolicyName: policy.Name, PolicyType: policy.Type, Index: index, Statement: statement}
+			for _, grant := range statementGrants(statement) {
+				key := grant.key()
+				if existing, ok := grants[key]; ok {
+					existing.Sources = append(existing.Sources, match)
+					continue
+				}
+				grant.Sources = []IAMStatementMatch{match}
+				grants[key] = &grant
+				order = append(order, key)
+			}
+		}
+	}
+	result := make([]IAMGrant, 0, len(order))
+	for _, key := range order {
+		result = append(result, *grants[key])
+	}
+	sortIAMGrants(result)
+	return result
+}
+
+func statementGrants(statement IAMPolicyDocumentStatement) []IAMGrant {
+	actions := statement.GetActions()
+	resources := statement.GetResources()
+	if len(resources) == 0 {
+		resources = []string{""}
+	}
+	condition := statement.GetCondition()
+	result := make([]IAMGrant, 0, len(actions)*len(resources))
+	for _, action := range actions {
+		for _, resource := range resources {
+			result = append(result, IAMGrant{Effect: statement.Effect, Action: action, Resource: resource, Condition: condition})
+		}
+	}
+	return result
+}
+
+func sortIAMGrants(grants []IAMGrant) {
+	sort.SliceStable(grants, func(i, j int) bool {
+		if !strings.EqualFold(grants[i].Action, grants[j].Action) {
+			return strings.ToLower(grants[i].Action) < strings.ToLower(grants[j].Action)
+		}
+		if grants[i].Resource != grants[j].Resource {
+			return grants[i].Resource < grants[j].Resource
+		}
+		return grants[i].Effect < grants[j].Effect
+	})
+}
+
+// CompareIAMPrincipals returns the grants that only one of the principals has.
+// Grants are compared literally, so s3:Get* and s3:GetObject are reported as
+// different grants even though one includes the other.
+func CompareIAMPrincipals(first IAMPrincipalPolicies, second IAMPrincipalPolicies) IAMPolicyComparison {
+	result := IAMPolicyComparison{First: first, Second: second}
+	firstGrants := NormalizeIAMGrants(first)
+	secondGrants := NormalizeIAMGrants(second)
+	secondKeys := make(map[string]bool, len(secondGrants))
+	for _, grant := range secondGrants {
+		secondKeys[grant.key()] = true
+	}
+	firstKeys := make(map[string]bool, len(firstGrants))
+	for _, grant := range firstGrants {
+		firstKeys[grant.key()] = true
+		if secondKeys[grant.key()] {
+			result.SharedCount++
+			continue
+		}
+		result.OnlyInFirst = append(result.OnlyInFirst, grant)
+	}
+	for _, grant := range secondGrants {
+		if !firstKeys[grant.key()] {
+			result.OnlyInSecond = append(result.OnlyInSecond, grant)
+		}
+	}
+	return result
+}
+
+// GetComparablePrincipalPolicies collects the policies of a role, user, or
+// SSO permission set. Permission sets are identified by their ARN or by their
+// name prefixed with permission-set/, everything else is looked up as a role
+// or user. The SSO instance is only retrieved for permission sets, whose
+// customer managed policies and permissions boundary are read from the
+// provided account. If a customer managed policy doesn't exist in that
+// account an error is returned, as the comparison would be incomplete.
+func GetComparablePrincipalPolicies(principal string, accountID string, iamSvc IAMClient, ssoSvc SSOPermissionSetPoliciesAPI) (IAMPrincipalPolicies, error) {
+	name, ok := parsePermissionSetPrincipal(principal)
+	if !ok {
+		return GetIAMPrincipalPolicies(principal, iamSvc)
+	}
+	instance, err := GetSSOAccountInstance(ssoSvc)
+	if err != nil {
+		return IAMPrincipalPolicies{}, err
+	}
+	permissionset, found := instance.GetPermissionSet(name)
+	if !found {
+		return IAMPrincipalPolicies{}, fmt.Errorf("no permission set found with the name or ARN %s", name)
+	}
+	if err := permissionset.AddDetails(ssoSvc); err != nil {
+		return IAMPrincipalPolicies{}, err
+	}
+	if err := permissionset.AddPolicyDocuments(accountID, iamSvc); err != nil {
+		return IAMPrincipalPolicies{}, err
+	}
+	if missing := permissionset.MissingCustomerManagedPolicies(); len(missing) > 0 {
+		return IAMPrincipalPolicies{}, fmt.Errorf("customer managed policies of permission set %s don't exist in account %s: %s", permissionset.Name, accountID, strings.Join(missing, ", "))
+	}
+	return GetPermissionSetPolicies(permissionset, iamSvc)
+}
+
+// parsePermissionSetPrincipal returns the name or ARN of the permission set
+// and whether the principal refers to a permission set
+func parsePermissionSetPrincipal(principal string) (string, bool) {
+	if strings.HasPrefix(principal, "arn:") && strings.Contains(principal, ":permissionSet/") {
+		return principal, true
+	}
+	if name, ok := strings.CutPrefix(principal, "permission-set/"); ok {
+		return name, true
+	}
+	return "", false
+}
+
+// GetPermissionSetPolicies parses the inline policy of the permission set and
+// retrieves the documents of its managed policies through IAM, unless they
+// were already added with AddPolicyDocuments. Customer managed policies and
+// the permissions boundary are only included once their documents are added.
+func GetPermissionSetPolicies(permissionset SSOPermissionSet, svc IAMClient) (IAMPrincipalPolicies, error) {
+	result := IAMPrincipalPolicies{Name: permissionset.Name, Type: IAMObjectTypePermissionSet}
+	if permissionset.InlinePolicy != "" {
+		policy, err := ParseIAMPolicyDocument(permissionset.Name, IAMPolicyTypeInline, permissionset.InlinePolicy)
+		if err != nil {
+			return result, err
+		}
+		result.Policies = append(result.Policies, policy)
+	}
+	for _, managed := range permissionset.ManagedPolicies {
+		document := managed.Policy
+		if document == "" {
+			document = getAttachedPolicy(&managed.Arn, svc)
+		}
+		policy, err := ParseIAMPolicyDocument(managed.Name, IAMPolicyTypeAttached, document)
+		if err != nil {
+			return result, err
+		}
+		result.Policies = append(result.Policies, policy)
+	}
+	for _, customer := range permissionset.CustomerManagedPolicies {
+		if customer.Policy == "" {
+			continue
+		}
+		policy, err := ParseIAMPolicyDocument(customer.Name, IAMPolicyTypeAttached, customer.Policy)
+		if err != nil {
+			return result, err
+		}
+		result.Policies = append(result.Policies, policy)
+	}
+	if boundary := permissionset.PermissionsBoundary; boundary != nil && boundary.Policy != "" {
+		policy, err := ParseIAMPolicyDocument(boundary.Name(), IAMPolicyTypePermissionsBoundary, boundary.Policy)
+		if err != nil {
+			return result, err
+		}
+		result.PermissionsBoundary = &policy
+	}
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func grantSummary(grants []IAMGrant) []string {
+	result := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		result = append(result, grant.Effect+" "+grant.Action+" "+grant.Resource)
+	}
+	return result
+}
+
+func TestNormalizeIAMGrants(t *testing.T) {
+	principal := IAMPrincipalPolicies{Name: "dev", Type: IAMObjectTypeRole, Policies: []IAMPolicyDocument{
+		mustParsePolicy(t, "First", IAMPolicyTypeInline, `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","s3:PutObject"],"Resource":["arn:aws:s3:::a/*","arn:aws:s3:::b/*"]}]}`),
+		mustParsePolicy(t, "Second", IAMPolicyTypeAttached, `{"Statement":[{"Effect":"Allow","Action":"S3:GetObject","Resource":"arn:aws:s3:::a/*"},{"Effect":"Deny","NotAction":"iam:*","NotResource":"arn:aws:iam::*:role/x"}]}`),
+	}}
+	grants := NormalizeIAMGrants(principal)
+	want := []string{
+		"Deny NOT iam:* NOT arn:aws:iam::*:role/x",
+		"Allow s3:GetObject arn:aws:s3:::a/*",
+		"Allow s3:GetObject arn:aws:s3:::b/*",
+		"Allow s3:PutObject arn:aws:s3:::a/*",
+		"Allow s3:PutObject arn:aws:s3:::b/*",
+	}
+	got := grantSummary(grants)
+	if len(got) != len(want) {
+		t.Fatalf("NormalizeIAMGrants() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("grant %d = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if len(grants[1].Sources) != 2 {
+		t.Errorf("s3:GetObject on a/* has %d sources, want 2", len(grants[1].Sources))
+	}
+}
+
+func TestCompareIAMPrincipals(t *testing.T) {
+	dev := IAMPrincipalPolicies{Name: "dev", Policies: []IAMPolicyDocument{
+		mustParsePolicy(t, "Dev", IAMPolicyTypeInline, `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject","kms:Decrypt"],"Resource":"*"}]}`),
+	}}
+	prod := IAMPrincipalPolicies{Name: "prod", Policies: []IAMPolicyDocument{
+		mustParsePolicy(t, "Prod", IAMPolicyTypeInline, `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"},{"Effect":"Allow","Action":"kms:Decrypt","Resource":"*","Condition":{"StringEquals":{"kms:ViaService":"s3.amazonaws.com"}}}]}`),
+	}}
+	comparison := CompareIAMPrincipals(dev, prod)
+	if comparison.SharedCount != 1 {
+		t.Errorf("SharedCount = %d, want 1", comparison.SharedCount)
+	}
+	if got := grantSummary(comparison.OnlyInFirst); len(got) != 1 || got[0] != "Allow kms:Decrypt *" {
+		t.Errorf("OnlyInFirst = %v", got)
+	}
+	if len(comparison.OnlyInSecond) != 1 || comparison.OnlyInSecond[0].Condition != `{"StringEquals":{"kms:ViaService":"s3.amazonaws.com"}}` {
+		t.Errorf("OnlyInSecond = %+v", comparison.OnlyInSecond)
+	}
+}
+
+func TestParsePermissionSetPrincipal(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		ok   bool
+	}{
+		"permission-set/AdministratorAccess":            {"AdministratorAccess", true},
+		"arn:aws:sso:::permissionSet/ssoins-123/ps-456": {"arn:aws:sso:::permissionSet/ssoins-123/ps-456", true},
+		"role/dev": {"", false},
+		"arn:aws:iam::123456789012:role/permission-set/AdminAccess": {"", false},
+	}
+	for principal, want := range tests {
+		name, ok := parsePermissionSetPrincipal(principal)
+		if name != want.name || ok != want.ok {
+			t.Errorf("parsePermissionSetPrincipal(%s) = %s, %v, want %s, %v", principal, name, ok, want.name, want.ok)
+		}
+	}
+}
+
+func TestGetPermissionSetPolicies(t *testing.T) {
+	permissionset := SSOPermissionSet{
+		Name:            "Developer",
+		InlinePolicy:    `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+		ManagedPolicies: []SSOPolicy{{Arn: "arn:aws:iam::aws:policy/PowerUserAccess", Name: "PowerUserAccess"}},
+	}
+	principal, err := GetPermissionSetPolicies(permissionset, &mockIAMClient{})
+	if err != nil {
+		t.Fatalf("GetPermissionSetPolicies() error = %v", err)
+	}
+	if principal.Type != IAMObjectTypePermissionSet || len(principal.Policies) != 2 {
+		t.Fatalf("GetPermissionSetPolicies() = %+v", principal)
+	}
+	if principal.Policies[0].Type != IAMPolicyTypeInline || principal.Policies[1].Name != "PowerUserAccess" {
+		t.Errorf("policies = %s (%s), %s (%s)", principal.Policies[0].Name, principal.Policies[0].Type, principal.Policies[1].Name, principal.Policies[1].Type)
+	}
+}
@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAMCredentialReportRootUser is the name used for the root user in the credential report
+const IAMCredentialReportRootUser = "<root_account>"
+
+// credentialReportPollInterval is the time between checks whether the
+// credential report has finished generating
+var credentialReportPollInterval = 2 * time.Second
+
+// credentialReportMaxAttempts is the number of times the credential report
+// generation is checked before giving up
+const credentialReportMaxAttempts = 30
+
+// CredentialReportAPI is the IAM API surface needed to generate and retrieve
+// the credential report
+type CredentialReportAPI interface {
+	GenerateCredentialReport(ctx context.Context, params *iam.GenerateCredentialReportInput, optFns ...func(*iam.Options)) (*iam.GenerateCredentialReportOutput, error)
+	GetCredentialReport(ctx context.Context, params *iam.GetCredentialReportInput, optFns ...func(*iam.Options)) (*iam.GetCredentialReportOutput, error)
+}
+
+// IAMCredentialReportAccessKey contains the credential report details of one
+// of the (at most two) access keys of a user
+type IAMCredentialReportAccessKey struct {
+	Number          int
+	Active          bool
+	LastRotated     time.Time
+	LastUsed        time.Time
+	LastUsedRegion  string
+	LastUsedService string
+}
+
+// AgeInDays returns the number of days since the key was last rotated
+func (key IAMCredentialReportAccessKey) AgeInDays(now time.Time) int {
+	return int(now.Sub(key.LastRotated).Hours() / 24)
+}
+
+// IAMCredentialReportEntry is a single user in the credential report
+type IAMCredentialReportEntry struct {
+	User                string
+	Arn                 string
+	Created             time.Time
+	PasswordEnabled     bool
+	PasswordLastUsed    time.Time
+	PasswordLastChanged time.Time
+	MFAActive           bool
+	AccessKeys          []IAMCredentialReportAccessKey
+}
+
+// IsRoot returns whether the entry is for the root user
+func (entry IAMCredentialReportEntry) IsRoot() bool {
+	return entry.User == IAMCredentialReportRootUser
+}
+
+// ActiveAccessKeys returns the access keys that are active
+func (entry IAMCredentialReportEntry) ActiveAccessKeys() []IAMCredentialReportAccessKey {
+	var result []IAMCredentialReportAccessKey
+	for _, key := range entry.AccessKeys {
+		if key.Active {
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// LastUsed returns the most recent use of the password or any of the access keys
+func (entry IAMCredentialReportEntry) LastUsed() time.Time {
+	result := entry.PasswordLastUsed
+	for _, key := range entry.AccessKeys {
+		if key.LastUsed.After(result) {
+			result = key.LastUsed
+		}
+	}
+	return result
+}
+
+// Findings returns the key hygiene issues for the entry: active access keys
+// older than maxKeyAge days, console access without MFA, and any use of the
+// root user or its access keys
+func (entry IAMCredentialReportEntry) Findings(maxKeyAge int, now time.Time) []string {
+	var result []string
+	if entry.PasswordEnabled && !entry.MFAActive {
+		result = append(result, "Console access without MFA")
+	}
+	for _, key := range entry.ActiveAccessKeys() {
+		if !key.LastRotated.IsZero() && key.AgeInDays(now) > maxKeyAge {
+			result = append(result, fmt.Sprintf("Access key %d is %d days old", key.Number, key.AgeInDays(now)))
+		}
+	}
+	if entry.IsRoot() {
+		if !entry.MFAActive {
+			result = append(result, "Root account has no MFA")
+		}
+		if !entry.PasswordLastUsed.IsZero() {
+			result = append(result, "Root account used on "+entry.PasswordLastUsed.Format(time.DateOnly))
+		}
+		for _, key := range entry.ActiveAccessKeys() {
+			result = append(result, fmt.Sprintf("Root account has active access key %d", key.Number))
+		}
+	}
+	return result
+}
+
+// GetCredentialReport generates the IAM credential report, waits for it to
+// be complete, and returns the parsed entries. This provides the password and
+// access key details of all users in a single call, instead of several calls
+// per user.
+func GetCredentialReport(svc CredentialReportAPI) []IAMCredentialReportEntry {
+	for attempt := 0; ; attempt++ {
+		resp, err := svc.GenerateCredentialReport(context.TODO(), &iam.GenerateCredentialReportInput{})
+		if err != nil {
+			panic(err)
+		}
+		if resp.State == types.ReportStateTypeComplete {
+			break
+		}
+		if attempt >= credentialReportMaxAttempts {
+			panic(fmt.Errorf("credential report is still in state %s after %d attempts", resp.State, attempt+1))
+		}
+		time.Sleep(credentialReportPollInterval)
+	}
+	resp, err := svc.GetCredentialReport(context.TODO(), &iam.GetCredentialReportInput{})
+	if err != nil {
+		panic(err)
+	}
+	entries, err := ParseCredentialReport(resp.Content)
+	if err != nil {
+		panic(err)
+	}
+	return entries
+}
+
+// ParseCredentialReport parses the CSV content of the credential report.
+// Columns are looked up by their header, so the order doesn't matter.
+func ParseCredentialReport(content []byte) ([]IAMCredentialReportEntry, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse credential report: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("credential report is empty")
+	}
+	columns := make(map[string]int)
+	for index, header := range records[0] {
+		columns[header] = index
+	}
+	if _, ok := columns["user"]; !ok {
+		return nil, fmt.Errorf("credential report is missing the user column")
+	}
+	var result []IAMCredentialReportEntry
+	for _, record := range records[1:] {
+		value := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(record) {
+				return record[index]
+			}
+			return ""
+		}
+		entry := IAMCredentialReportEntry{
+			User:                value("user"),
+			Arn:                 value("arn"),
+			Created:             parseCredentialReportTime(value("user_creation_time")),
+			PasswordEnabled:     value("password_enabled") == "true",
+			PasswordLastUsed:    parseCredentialReportTime(value("password_last_used")),
+			PasswordLastChanged: parseCredentialReportTime(value("password_last_changed")),
+			MFAActive:           value("mfa_active") == "true",
+		}
+		for number := 1; number <= 2; number++ {
+			prefix := fmt.Sprintf("access_key_%d_", number)
+			entry.AccessKeys = append(entry.AccessKeys, IAMCredentialReportAccessKey{
+				Number:          number,
+				Active:          value(prefix+"active") == "true",
+				LastRotated:     parseCredentialReportTime(value(prefix + "last_rotated")),
+				LastUsed:        parseCredentialReportTime(value(prefix + "last_used_date")),
+				LastUsedRegion:  credentialReportValue(value(prefix + "last_used_region")),
+				LastUsedService: credentialReportValue(value(prefix + "last_used_service")),
+			})
+		}
+		result = append(result, entry)
+	}
+	return result, nil
+}
+
+// parseCredentialReportTime parses a timestamp from the credential report.
+// Placeholders such as N/A, no_information, and not_supported result in a
+// zero time.
+func parseCredentialReportTime(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+// credentialReportValue returns the value, or an empty string if it's a placeholder
+func credentialReportValue(value string) string {
+	switch value {
+	case "N/A", "no_information", "not_supported":
+		return ""
+	}
+	return value
+}
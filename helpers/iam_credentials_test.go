@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+const testCredentialReport = `user,arn,user_creation_time,password_enabled,password_last_used,password_last_changed,password_next_rotation,mfa_active,access_key_1_active,access_key_1_last_rotated,access_key_1_last_used_date,access_key_1_last_used_region,access_key_1_last_used_service,access_key_2_active,access_key_2_last_rotated,access_key_2_last_used_date,access_key_2_last_used_region,access_key_2_last_used_service,cert_1_active,cert_1_last_rotated,cert_2_active,cert_2_last_rotated
+<root_account>,arn:aws:iam::123456789012:root,2020-01-01T00:00:00+00:00,not_supported,2024-05-20T10:00:00+00:00,not_supported,not_supported,false,false,N/A,N/A,N/A,N/A,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+alice,arn:aws:iam::123456789012:user/alice,2021-01-01T00:00:00+00:00,true,2024-05-30T08:00:00+00:00,2021-01-01T00:00:00+00:00,N/A,false,true,2023-01-01T00:00:00+00:00,2024-05-31T12:00:00+00:00,us-east-1,s3,false,N/A,N/A,N/A,N/A,false,N/A,false,N/A
+bob,arn:aws:iam::123456789012:user/bob,2022-01-01T00:00:00+00:00,false,N/A,N/A,N/A,true,true,2024-05-01T00:00:00+00:00,no_information,N/A,N/A,true,2024-04-01T00:00:00+00:00,N/A,N/A,N/A,false,N/A,false,N/A
+`
+
+type mockCredentialReportClient struct {
+	states        []types.ReportStateType
+	generateCalls int
+}
+
+func (m *mockCredentialReportClient) GenerateCredentialReport(_ context.Context, _ *iam.GenerateCredentialReportInput, _ ...func(*iam.Options)) (*iam.GenerateCredentialReportOutput, error) {
+	state := m.states[min(m.generateCalls, len(m.states)-1)]
+	m.generateCalls++
+	return &iam.GenerateCredentialReportOutput{State: state}, nil
+}
+
+func (m *mockCredentialReportClient) GetCredentialReport(_ context.Context, _ *iam.GetCredentialReportInput, _ ...func(*iam.Options)) (*iam.GetCredentialReportOutput, error) {
+	return &iam.GetCredentialReportOutput{Content: []byte(testCredentialReport)}, nil
+}
+
+func TestGetCredentialReport(t *testing.T) {
+	interval := credentialReportPollInterval
+	credentialReportPollInterval = 0
+	defer func() { credentialReportPollInterval = interval }()
+
+	mock := &mockCredentialReportClient{states: []types.ReportStateType{types.ReportStateTypeStarted, types.ReportStateTypeInprogress, types.ReportStateTypeComplete}}
+	entries := GetCredentialReport(mock)
+	if mock.generateCalls != 3 {
+		t.Errorf("GenerateCredentialReport called %d times, want 3", mock.generateCalls)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d entries, want 3", len(entries))
+	}
+	if !entries[0].IsRoot() || entries[1].IsRoot() {
+		t.Errorf("IsRoot() not detected correctly")
+	}
+	alice := entries[1]
+	if !alice.PasswordEnabled || alice.MFAActive {
+		t.Errorf("alice PasswordEnabled/MFAActive = %v/%v, want true/false", alice.PasswordEnabled, alice.MFAActive)
+	}
+	if alice.AccessKeys[0].LastUsedService != "s3" || alice.AccessKeys[0].LastUsedRegion != "us-east-1" {
+		t.Errorf("alice access key 1 last used = %s in %s", alice.AccessKeys[0].LastUsedService, alice.AccessKeys[0].LastUsedRegion)
+	}
+	if !alice.LastUsed().Equal(time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("alice LastUsed() = %v", alice.LastUsed())
+	}
+	bob := entries[2]
+	if len(bob.ActiveAccessKeys()) != 2 || !bob.AccessKeys[0].LastUsed.IsZero() {
+		t.Errorf("bob access keys not parsed correctly: %+v", bob.AccessKeys)
+	}
+}
+
+func TestIAMCredentialReportEntry_Findings(t *testing.T) {
+	entries, err := ParseCredentialReport([]byte(testCredentialReport))
+	if err != nil {
+		t.Fatalf("ParseCredentialReport() error = %v", err)
+	}
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		user string
+		want []string
+	}{
+		{IAMCredentialReportRootUser, []string{"Root account has no MFA", "Root account used on 2024-05-20"}},
+		{"alice", []string{"Console access without MFA", "Access key 1 is 517 days old"}},
+		{"bob", nil},
+	}
+	for i, tt := range tests {
+		if entries[i].User != tt.user {
+			t.Fatalf("entry %d is %s, want %s", i, entries[i].User, tt.user)
+		}
+		if got := entries[i].Findings(90, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s Findings() = %v, want %v", tt.user, got, tt.want)
+		}
+	}
+}
+
+func TestParseCredentialReport_Invalid(t *testing.T) {
+	if _, err := ParseCredentialReport([]byte("")); err == nil {
+		t.Errorf("expected an error for an empty report")
+	}
+	if _, err := ParseCredentialReport([]byte("arn,mfa_active\narn:aws:iam::123456789012:user/alice,true\n")); err == nil {
+		t.Errorf("expected an error for a report without user column")
+	}
+}
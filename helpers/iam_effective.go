@@ -0,0 +1,146 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+)
+
+// Layers of the policy evaluation that can decide an action
+const (
+	IAMLayerSCP                 = "Service Control Policy"
+	IAMLayerPermissionsBoundary = "Permissions Boundary"
+	IAMLayerIdentity            = "Identity Policy"
+)
+
+// OrganizationDescriptionAPI defines the Organizations call used to look up
+// the management account
+type OrganizationDescriptionAPI interface {
+	DescribeOrganization(ctx context.Context, params *organizations.DescribeOrganizationInput, optFns ...func(*organizations.Options)) (*organizations.DescribeOrganizationOutput, error)
+}
+
+// IAMEffectiveResult is the outcome of evaluating an action against the
+// SCPs, permissions boundary, and identity policies of a principal, and the
+// layer that decided it
+type IAMEffectiveResult struct {
+	IAMEvaluationResult
+	Layer string
+}
+
+// scpLevel is a level of the organization with its parsed SCPs
+type scpLevel struct {
+	entry    OrganizationEntry
+	policies []IAMPolicyDocument
+}
+
+// GetManagementAccountID returns the ID of the organization's management
+// account, which SCPs don't apply to
+func GetManagementAccountID(svc OrganizationDescriptionAPI) (string, error) {
+	resp, err := svc.DescribeOrganization(context.TODO(), &organizations.DescribeOrganizationInput{})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe organization: %w", err)
+	}
+	if resp.Organization == nil {
+		return "", fmt.Errorf("no organization found")
+	}
+	return aws.ToString(resp.Organization.MasterAccountId), nil
+}
+
+// EvaluateEffectivePermissions evaluates every action against the resource
+// for the principal, combining the SCPs inherited along the levels with the
+// permissions boundary and identity policies:
+//   - an explicit deny in an SCP, the boundary, or an identity policy wins
+//   - every level of the organization needs an SCP that allows the action
+//   - the permissions boundary, if set, needs to allow the action
+//   - an identity policy needs to allow the action
+//
+// When levels is empty, SCPs aren't evaluated, as is the case for the
+// management account. Conditions of identity policies and the boundary are
+// treated as met, as in EvaluateIAMPolicies. SCP Deny statements with
+// conditions, such as region restrictions, don't decide the result; they are
+// added to an allowed result, which is then conditional.
+func EvaluateEffectivePermissions(principal IAMPrincipalPolicies, levels []OrganizationPolicyLevel, actions []string, resource string) ([]IAMEffectiveResult, error) {
+	parsed := make([]scpLevel, 0, len(levels))
+	for _, level := range levels {
+		parsedLevel := scpLevel{entry: level.Entry}
+		for _, policy := range level.Policies {
+			document, err := ParseSCP(policy)
+			if err != nil {
+				return nil, err
+			}
+			parsedLevel.policies = append(parsedLevel.policies, document)
+		}
+		parsed = append(parsed, parsedLevel)
+	}
+	result := make([]IAMEffectiveResult, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, evaluateEffectiveAction(principal, parsed, action, resource))
+	}
+	return result, nil
+}
+
+func evaluateEffectiveAction(principal IAMPrincipalPolicies, levels []scpLevel, action string, resource string) IAMEffectiveResult {
+	identity := EvaluateIAMPolicies(principal, action, resource)
+	var scpAllows, scpDenies, conditionalDenies []IAMStatementMatch
+	var blockingLevel *OrganizationEntry
+	for _, level := range levels {
+		allows, denies := matchingStatements(level.policies, action, resource)
+		for _, deny := range denies {
+			if deny.IsConditional() {
+				conditionalDenies = append(conditionalDenies, deny)
+			} else {
+				scpDenies = append(scpDenies, deny)
+			}
+		}
+		scpAllows = append(scpAllows, allows...)
+		if len(allows) == 0 && blockingLevel == nil {
+			blockingLevel = &level.entry
+		}
+	}
+	result := IAMEffectiveResult{IAMEvaluationResult: identity}
+	switch {
+	case len(scpDenies) > 0:
+		result.Decision = IAMDecisionExplicitDeny
+		result.Reason = "Explicitly denied by an SCP"
+		result.DecidedBy = scpDenies
+		result.Layer = IAMLayerSCP
+	case identity.Decision == IAMDecisionExplicitDeny:
+		result.Layer = IAMLayerIdentity
+		if deniedOnlyByBoundary(identity.DecidedBy) {
+			result.Layer = IAMLayerPermissionsBoundary
+		}
+	case blockingLevel != nil:
+		result.Decision = IAMDecisionImplicitDeny
+		result.Reason = fmt.Sprintf("No SCP attached to %s allows the action", blockingLevel.String())
+		result.DecidedBy = nil
+		result.Layer = IAMLayerSCP
+	case identity.Decision == IAMDecisionImplicitDeny:
+		result.Layer = IAMLayerIdentity
+		if allows, _ := matchingStatements(principal.Policies, action, resource); len(allows) > 0 {
+			result.Layer = IAMLayerPermissionsBoundary
+		}
+	default:
+		if len(levels) > 0 {
+			result.Reason += ", and the SCPs on every level"
+			result.DecidedBy = append(result.DecidedBy, scpAllows...)
+		}
+		if len(conditionalDenies) > 0 {
+			result.Reason += ", unless the conditions of an SCP Deny statement match"
+			result.DecidedBy = append(result.DecidedBy, conditionalDenies...)
+		}
+	}
+	return result
+}
+
+// deniedOnlyByBoundary returns whether all denying statements come from the
+// permissions boundary
+func deniedOnlyByBoundary(matches []IAMStatementMatch) bool {
+	for _, match := range matches {
+		if match.PolicyType != IAMPolicyTypePermissionsBoundary {
+			return false
+		}
+	}
+	return len(matches) > 0
+}
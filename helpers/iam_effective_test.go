@@ -0,0 +1,79 @@
+package helpers
+
+import (
+	"testing"
+)
+
+func TestEvaluateEffectivePermissions(t *testing.T) {
+	levels, err := GetPolicyInheritance(testOrganization(), "111111111111", testSCPs())
+	if err != nil {
+		t.Fatalf("GetPolicyInheritance() error = %v", err)
+	}
+	boundary := mustParsePolicy(t, "Boundary", IAMPolicyTypePermissionsBoundary, `{"Statement":[{"Effect":"Allow","Action":["s3:*","cloudtrail:*","iam:*"],"Resource":"*"},{"Effect":"Deny","Action":"s3:DeleteBucket","Resource":"*"}]}`)
+	principal := IAMPrincipalPolicies{
+		Name: "deploy",
+		Type: IAMObjectTypeRole,
+		Policies: []IAMPolicyDocument{
+			mustParsePolicy(t, "Deploy", IAMPolicyTypeInline, `{"Statement":[{"Effect":"Allow","Action":["s3:*","cloudtrail:*","ec2:*","iam:PassRole"],"Resource":"*"},{"Effect":"Deny","Action":"iam:PassRole","Resource":"*"}]}`),
+		},
+		PermissionsBoundary: &boundary,
+	}
+	actions := []string{"s3:GetObject", "cloudtrail:StopLogging", "s3:DeleteBucket", "ec2:RunInstances", "iam:PassRole", "iam:CreateRole"}
+	results, err := EvaluateEffectivePermissions(principal, levels, actions, "*")
+	if err != nil {
+		t.Fatalf("EvaluateEffectivePermissions() error = %v", err)
+	}
+	tests := []struct {
+		decision string
+		layer    string
+	}{
+		{IAMDecisionAllowed, ""},
+		{IAMDecisionExplicitDeny, IAMLayerSCP},
+		{IAMDecisionExplicitDeny, IAMLayerPermissionsBoundary},
+		{IAMDecisionImplicitDeny, IAMLayerPermissionsBoundary},
+		{IAMDecisionExplicitDeny, IAMLayerIdentity},
+		{IAMDecisionImplicitDeny, IAMLayerIdentity},
+	}
+	for i, want := range tests {
+		got := results[i]
+		if got.Decision != want.decision || got.Layer != want.layer {
+			t.Errorf("%s = %s (%s), want %s (%s): %s", actions[i], got.Decision, got.Layer, want.decision, want.layer, got.Reason)
+		}
+	}
+	// The region restriction SCP has a condition, so it only makes the result conditional
+	if !results[0].IsConditional() {
+		t.Errorf("s3:GetObject isn't conditional: %s", results[0].Reason)
+	}
+}
+
+func TestEvaluateEffectivePermissions_SCPLevelWithoutAllow(t *testing.T) {
+	policies := testSCPs()
+	// Only allow EC2 on the Prod OU, so other services are implicitly denied there
+	policies[0].Targets = policies[0].Targets[:2]
+	policies[2].Content = `{"Statement":[{"Effect":"Allow","Action":"ec2:*","Resource":"*"}]}`
+	policies = append(policies, OrganizationPolicy{ID: "p-account", Name: "AccountAll", Content: `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+		Targets: []OrganizationPolicyTarget{{ID: "111111111111"}}})
+	levels, err := GetPolicyInheritance(testOrganization(), "111111111111", policies)
+	if err != nil {
+		t.Fatalf("GetPolicyInheritance() error = %v", err)
+	}
+	principal := IAMPrincipalPolicies{Name: "admin", Type: IAMObjectTypeRole, Policies: []IAMPolicyDocument{
+		mustParsePolicy(t, "Admin", IAMPolicyTypeAttached, `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`),
+	}}
+	results, err := EvaluateEffectivePermissions(principal, levels, []string{"s3:GetObject", "ec2:RunInstances"}, "*")
+	if err != nil {
+		t.Fatalf("EvaluateEffectivePermissions() error = %v", err)
+	}
+	if results[0].Decision != IAMDecisionImplicitDeny || results[0].Layer != IAMLayerSCP || results[0].Reason != "No SCP attached to Prod (ou-prod) allows the action" {
+		t.Errorf("s3:GetObject = %s (%s): %s", results[0].Decision, results[0].Layer, results[0].Reason)
+	}
+	if !results[1].IsAllowed() || results[1].Reason != "Allowed by an identity policy, and the SCPs on every level, unless the conditions of an SCP Deny statement match" {
+		t.Errorf("ec2:RunInstances = %s: %s", results[1].Decision, results[1].Reason)
+	}
+
+	// Without levels, such as for the management account, SCPs are skipped
+	results, err = EvaluateEffectivePermissions(principal, nil, []string{"s3:GetObject"}, "*")
+	if err != nil || !results[0].IsAllowed() {
+		t.Errorf("s3:GetObject without SCPs = %+v, %v", results, err)
+	}
+}
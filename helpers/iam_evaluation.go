@@ -0,0 +1,370 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAM policy evaluation decisions
+const (
+	IAMDecisionAllowed      = "Allowed"
+	IAMDecisionExplicitDeny = "Explicit deny"
+	IAMDecisionImplicitDeny = "Implicit deny"
+)
+
+// IAMPrincipalPolicies holds the identity policies that apply to a role or
+// user, including the ones a user inherits from its groups, and the
+// permissions boundary if one is set
+type IAMPrincipalPolicies struct {
+	Name                string
+	Type                string
+	Policies            []IAMPolicyDocument
+	PermissionsBoundary *IAMPolicyDocument
+}
+
+// IAMStatementMatch is a policy statement that applies to the evaluated
+// action and resource
+type IAMStatementMatch struct {
+	PolicyName string
+	PolicyType string
+	Index      int
+	Statement  IAMPolicyDocumentStatement
+}
+
+// String returns a description of the statement, such as
+// "ReadOnly (Attached Policy) statement 2 (Sid: AllowS3)"
+func (match IAMStatementMatch) String() string {
+	result := fmt.Sprintf("%s (%s) statement %d", match.PolicyName, match.PolicyType, match.Index+1)
+	if match.Statement.Sid != "" {
+		result += fmt.Sprintf(" (Sid: %s)", match.Statement.Sid)
+	}
+	return result
+}
+
+// IsConditional returns whether the statement has conditions, which can't be
+// evaluated offline
+func (match IAMStatementMatch) IsConditional() bool {
+	return match.Statement.Condition != nil
+}
+
+// IAMEvaluationResult is the outcome of evaluating whether a principal is
+// allowed to perform an action on a resource
+type IAMEvaluationResult struct {
+	Principal string
+	Action    string
+	Resource  string
+	Decision  string
+	Reason    string
+	DecidedBy []IAMStatementMatch
+}
+
+// IsAllowed returns whether the action is allowed
+func (result IAMEvaluationResult) IsAllowed() bool {
+	return result.Decision == IAMDecisionAllowed
+}
+
+// IsConditional returns whether any of the deciding statements has
+// conditions. As conditions aren't evaluated, the decision only holds when
+// those conditions are met.
+func (result IAMEvaluationResult) IsConditional() bool {
+	for _, match := range result.DecidedBy {
+		if match.IsConditional() {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateIAMPolicies evaluates the identity policies and permissions
+// boundary of a principal for the action and resource, following the IAM
+// evaluation logic for a single account:
+//   - an explicit deny in any policy, including the boundary, always wins
+//   - otherwise at least one identity policy statement needs to allow it
+//   - if a permissions boundary is set, it needs to allow it as well
+//
+// Statements with conditions are treated as if their conditions are met.
+// Resource-based policies, SCPs, and session policies aren't evaluated.
+func EvaluateIAMPolicies(principal IAMPrincipalPolicies, action string, resource string) IAMEvaluationResult {
+	result := IAMEvaluationResult{
+		Principal: principal.Name,
+		Action:    action,
+		Resource:  resource,
+	}
+	allows, denies := matchingStatements(principal.Policies, action, resource)
+	var boundaryAllows []IAMStatementMatch
+	if principal.PermissionsBoundary != nil {
+		var boundaryDenies []IAMStatementMatch
+		boundaryAllows, boundaryDenies = matchingStatements([]IAMPolicyDocument{*principal.PermissionsBoundary}, action, resource)
+		denies = append(denies, boundaryDenies...)
+	}
+	switch {
+	case len(denies) > 0:
+		result.Decision = IAMDecisionExplicitDeny
+		result.Reason = "Explicitly denied"
+		result.DecidedBy = denies
+	case len(allows) == 0:
+		result.Decision = IAMDecisionImplicitDeny
+		result.Reason = "No identity policy statement allows the action"
+	case principal.PermissionsBoundary != nil && len(boundaryAllows) == 0:
+		result.Decision = IAMDecisionImplicitDeny
+		result.Reason = fmt.Sprintf("The permissions boundary %s doesn't allow the action", principal.PermissionsBoundary.Name)
+	default:
+		result.Decision = IAMDecisionAllowed
+		result.Reason = "Allowed by an identity policy"
+		if principal.PermissionsBoundary != nil {
+			result.Reason += " and the permissions boundary"
+		}
+		result.DecidedBy = append(allows, boundaryAllows...)
+	}
+	return result
+}
+
+// matchingStatements returns the Allow and Deny statements from the policies
+// that apply to the action and resource
+func matchingStatements(policies []IAMPolicyDocument, action string, resource string) ([]IAMStatementMatch, []IAMStatementMatch) {
+	var allows, denies []IAMStatementMatch
+	for _, policy := range policies {
+		for index, statement := range policy.Statement {
+			if !statementMatches(statement, action, resource) {
+				continue
+			}
+			match := IAMStatementMatch{
+				PolicyName: policy.Name,
+				PolicyType: policy.Type,
+				Index:      index,
+				Statement:  statement,
+			}
+			if strings.EqualFold(statement.Effect, "Deny") {
+				denies = append(denies, match)
+			} else if strings.EqualFold(statement.Effect, "Allow") {
+				allows = append(allows, match)
+			}
+		}
+	}
+	return allows, denies
+}
+
+// statementMatches returns whether the statement applies to the action and
+// resource. Actions are matched case-insensitively, resources
+// case-sensitively. A statement without Action and NotAction (or Resource and
+// NotResource) is invalid and never matches.
+func statementMatches(statement IAMPolicyDocumentStatement, action string, resource string) bool {
+	if !statementMatchesAction(statement, action) {
+		return false
+	}
+	// Resource and NotResource use the same string or array format as Action
+	switch {
+	case statement.Resource != nil:
+		return anyWildcardMatch(normalizeActions(statement.Resource), resource, false)
+	case statement.NotResource != nil:
+		return !anyWildcardMatch(normalizeActions(statement.NotResource), resource, false)
+	}
+	return false
+}
+
+func anyWildcardMatch(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if ignoreCase {
+			if wildcardMatch(strings.ToLower(pattern), strings.ToLower(value)) {
+				return true
+			}
+		} else if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch matches the value against a pattern where * matches any
+// sequence of characters, including none, and ? matches a single character
+func wildcardMatch(pattern string, value string) bool {
+	p, v := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star = p
+			mark = v
+			p++
+		case star != -1:
+			p = star + 1
+			mark++
+			v = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// ParseIAMPolicyDocument parses a JSON policy document that has already been
+// URL decoded, such as the ones collected by GetUserDetails
+func ParseIAMPolicyDocument(name string, policytype string, document string) (IAMPolicyDocument, error) {
+	policy := IAMPolicyDocument{Name: name, Type: policytype}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		return policy, fmt.Errorf("failed to parse policy %s: %w", name, err)
+	}
+	return policy, nil
+}
+
+// GetIAMPrincipalPolicies collects the identity policies and permissions
+// boundary for a role or user. The principal can be provided as a name, an
+// ARN, or prefixed with role/ or user/ to indicate the type. Without a type,
+// roles are checked before users.
+func GetIAMPrincipalPolicies(principal string, svc IAMClient) (IAMPrincipalPolicies, error) {
+	principaltype, name := parseIAMPrincipal(principal)
+	if principaltype != IAMObjectTypeUser {
+		roles, _ := GetRolesAndPolicies(true, svc)
+		for _, role := range roles {
+			if role.Name == name {
+				return getRolePrincipalPolicies(role, svc)
+			}
+		}
+	}
+	if principaltype != IAMObjectTypeRole {
+		for _, user := range GetUserDetails(svc) {
+			if user.Name == name {
+				return getUserPrincipalPolicies(user, svc)
+			}
+		}
+	}
+	return IAMPrincipalPolicies{}, fmt.Errorf("no role or user found with the name %s", name)
+}
+
+// parseIAMPrincipal returns the type (if known) and name of the principal
+func parseIAMPrincipal(principal string) (string, string) {
+	if strings.HasPrefix(principal, "arn:") {
+		parts := strings.SplitN(principal, ":", 6)
+		if len(parts) == 6 {
+			principal = parts[5]
+		}
+	}
+	principaltype := ""
+	switch {
+	case strings.HasPrefix(principal, "role/"):
+		principaltype = IAMObjectTypeRole
+	case strings.HasPrefix(principal, "user/"):
+		principaltype = IAMObjectTypeUser
+	}
+	// Strip the type and any path
+	return principaltype, principal[strings.LastIndex(principal, "/")+1:]
+}
+
+func getRolePrincipalPolicies(role IAMRole, svc IAMClient) (IAMPrincipalPolicies, error) {
+	result := rolePrincipalPolicies(role)
+	resp, err := svc.GetRole(context.TODO(), &iam.GetRoleInput{RoleName: aws.String(role.Name)})
+	if err != nil {
+		return result, err
+	}
+	if resp.Role != nil {
+		result.PermissionsBoundary, err = getPermissionsBoundary(resp.Role.PermissionsBoundary, svc)
+	}
+	return result, err
+}
+
+func getUserPrincipalPolicies(user IAMUser, svc IAMClient) (IAMPrincipalPolicies, error) {
+	result, err := userPrincipalPolicies(user)
+	if err != nil {
+		return result, err
+	}
+	resp, err := svc.GetUser(context.TODO(), &iam.GetUserInput{UserName: aws.String(user.Name)})
+	if err != nil {
+		return result, err
+	}
+	if resp.User != nil {
+		result.PermissionsBoundary, err = getPermissionsBoundary(resp.User.PermissionsBoundary, svc)
+	}
+	return result, err
+}
+
+// rolePrincipalPolicies returns the inline and attached policies of the role
+func rolePrincipalPolicies(role IAMRole) IAMPrincipalPolicies {
+	result := IAMPrincipalPolicies{Name: role.Name, Type: IAMObjectTypeRole}
+	for _, name := range sortedKeys(role.InlinePolicies) {
+		result.Policies = append(result.Policies, *role.InlinePolicies[name])
+	}
+	for _, name := range sortedKeys(role.AttachedPolicies) {
+		result.Policies = append(result.Policies, *role.AttachedPolicies[name])
+	}
+	return result
+}
+
+// userPrincipalPolicies returns the inline and attached policies of the
+// user, followed by the ones it inherits from its groups
+func userPrincipalPolicies(user IAMUser) (IAMPrincipalPolicies, error) {
+	result := IAMPrincipalPolicies{Name: user.Name, Type: IAMObjectTypeUser}
+	policies, err := parsePolicyMaps(
+		policyMap{IAMPolicyTypeInline, user.InlinePolicies},
+		policyMap{IAMPolicyTypeAttached, user.AttachedPolicies},
+		policyMap{IAMPolicyTypeGroupInline, user.InlineGroupPolicies},
+		policyMap{IAMPolicyTypeGroupAttached, user.AttachedGroupPolicies},
+	)
+	result.Policies = policies
+	return result, err
+}
+
+// groupPrincipalPolicies returns the inline and attached policies of the group
+func groupPrincipalPolicies(group IAMGroup) (IAMPrincipalPolicies, error) {
+	result := IAMPrincipalPolicies{Name: group.Name, Type: IAMObjectTypeGroup}
+	policies, err := parsePolicyMaps(
+		policyMap{IAMPolicyTypeInline, group.InlinePolicies},
+		policyMap{IAMPolicyTypeAttached, group.AttachedPolicies},
+	)
+	result.Policies = policies
+	return result, err
+}
+
+// policyMap is a map of policy names to JSON policy documents of a single policy type
+type policyMap struct {
+	policytype string
+	policies   map[string]string
+}
+
+func parsePolicyMaps(sources ...policyMap) ([]IAMPolicyDocument, error) {
+	var result []IAMPolicyDocument
+	for _, source := range sources {
+		for _, name := range sortedKeys(source.policies) {
+			policy, err := ParseIAMPolicyDocument(name, source.policytype, source.policies[name])
+			if err != nil {
+				return result, err
+			}
+			result = append(result, policy)
+		}
+	}
+	return result, nil
+}
+
+func getPermissionsBoundary(boundary *types.AttachedPermissionsBoundary, svc IAMClient) (*IAMPolicyDocument, error) {
+	if boundary == nil || boundary.PermissionsBoundaryArn == nil {
+		return nil, nil
+	}
+	arn := aws.ToString(boundary.PermissionsBoundaryArn)
+	name := arn[strings.LastIndex(arn, "/")+1:]
+	policy, err := ParseIAMPolicyDocument(name, IAMPolicyTypePermissionsBoundary, getAttachedPolicy(boundary.PermissionsBoundaryArn, svc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load permissions boundary %s: %w", arn, err)
+	}
+	return &policy, nil
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
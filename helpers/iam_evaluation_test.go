@@ -0,0 +1,224 @@
+package helpers
+
+import (
+	"fmt"
+	"testing"
+)
+
+func mustParsePolicy(t *testing.T, name string, policytype string, document string) IAMPolicyDocument {
+	t.Helper()
+	policy, err := ParseIAMPolicyDocument(name, policytype, document)
+	if err != nil {
+		t.Fatalf("ParseIAMPolicyDocument() error = %v", err)
+	}
+	return policy
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"*", "anything", true},
+		{"s3:Get*", "s3:GetObject", true},
+		{"s3:Get*", "s3:PutObject", false},
+		{"s3:Get?bject", "s3:GetObject", true},
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket/path/file.txt", true},
+		{"arn:aws:s3:::bucket/*", "arn:aws:s3:::bucket", false},
+		{"arn:aws:s3:::*/logs/*", "arn:aws:s3:::bucket/logs/today", true},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.value); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateIAMPolicies(t *testing.T) {
+	readOnly := `{"Version":"2012-10-17","Statement":[{"Sid":"Read","Effect":"Allow","Action":["s3:Get*","s3:List*"],"Resource":"arn:aws:s3:::data/*"}]}`
+	denyTmp := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"arn:aws:s3:::data/tmp/*"}]}`
+	notAction := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","NotAction":"iam:*","Resource":"*"}]}`
+	notResource := `{"Version":"2012-10-17","Statement":[{"Effect":"Deny","Action":"*","NotResource":["arn:aws:s3:::data","arn:aws:s3:::data/*"]}]}`
+	conditional := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*","Condition":{"Bool":{"aws:SecureTransport":"true"}}}]}`
+	boundary := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:*","Resource":"*"}]}`
+
+	tests := []struct {
+		name            string
+		policies        []string
+		boundary        string
+		action          string
+		resource        string
+		wantDecision    string
+		wantDecidedBy   []string
+		wantConditional bool
+	}{
+		{
+			name:          "allowed by wildcard action",
+			policies:      []string{readOnly},
+			action:        "s3:GetObject",
+			resource:      "arn:aws:s3:::data/file.txt",
+			wantDecision:  IAMDecisionAllowed,
+			wantDecidedBy: []string{"policy-0 (Attached Policy) statement 1 (Sid: Read)"},
+		},
+		{
+			name:         "actions are case insensitive",
+			policies:     []string{readOnly},
+			action:       "S3:getobject",
+			resource:     "arn:aws:s3:::data/file.txt",
+			wantDecision: IAMDecisionAllowed,
+		},
+		{
+			name:         "resources are case sensitive",
+			policies:     []string{readOnly},
+			action:       "s3:GetObject",
+			resource:     "arn:aws:s3:::DATA/file.txt",
+			wantDecision: IAMDecisionImplicitDeny,
+		},
+		{
+			name:         "implicit deny for unmatched action",
+			policies:     []string{readOnly},
+			action:       "s3:PutObject",
+			resource:     "arn:aws:s3:::data/file.txt",
+			wantDecision: IAMDecisionImplicitDeny,
+		},
+		{
+			name:          "explicit deny wins over allow",
+			policies:      []string{readOnly, denyTmp},
+			action:        "s3:GetObject",
+			resource:      "arn:aws:s3:::data/tmp/file.txt",
+			wantDecision:  IAMDecisionExplicitDeny,
+			wantDecidedBy: []string{"policy-1 (Attached Policy) statement 1"},
+		},
+		{
+			name:         "NotAction allows everything else",
+			policies:     []string{notAction},
+			action:       "ec2:DescribeInstances",
+			resource:     "*",
+			wantDecision: IAMDecisionAllowed,
+		},
+		{
+			name:         "NotAction excludes matching actions",
+			policies:     []string{notAction},
+			action:       "iam:CreateUser",
+			resource:     "*",
+			wantDecision: IAMDecisionImplicitDeny,
+		},
+		{
+			name:         "NotResource deny outside of listed resources",
+			policies:     []string{notAction, notResource},
+			action:       "s3:GetObject",
+			resource:     "arn:aws:s3:::other/file.txt",
+			wantDecision: IAMDecisionExplicitDeny,
+		},
+		{
+			name:         "NotResource doesn't deny listed resources",
+			policies:     []string{notAction, notResource},
+			action:       "s3:GetObject",
+			resource:     "arn:aws:s3:::data/file.txt",
+			wantDecision: IAMDecisionAllowed,
+		},
+		{
+			name:         "permissions boundary limits allow",
+			policies:     []string{notAction},
+			boundary:     boundary,
+			action:       "ec2:DescribeInstances",
+			resource:     "*",
+			wantDecision: IAMDecisionImplicitDeny,
+		},
+		{
+			name:          "permissions boundary and identity policy both allow",
+			policies:      []string{readOnly},
+			boundary:      boundary,
+			action:        "s3:GetObject",
+			resource:      "arn:aws:s3:::data/file.txt",
+			wantDecision:  IAMDecisionAllowed,
+			wantDecidedBy: []string{"policy-0 (Attached Policy) statement 1 (Sid: Read)", "boundary (Permissions Boundary) statement 1"},
+		},
+		{
+			name:            "conditions are flagged",
+			policies:        []string{conditional},
+			action:          "s3:GetObject",
+			resource:        "arn:aws:s3:::data/file.txt",
+			wantDecision:    IAMDecisionAllowed,
+			wantConditional: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal := IAMPrincipalPolicies{Name: "test", Type: IAMObjectTypeRole}
+			for i, document := range tt.policies {
+				principal.Policies = append(principal.Policies, mustParsePolicy(t, fmt.Sprintf("policy-%d", i), IAMPolicyTypeAttached, document))
+			}
+			if tt.boundary != "" {
+				policy := mustParsePolicy(t, "boundary", IAMPolicyTypePermissionsBoundary, tt.boundary)
+				principal.PermissionsBoundary = &policy
+			}
+			result := EvaluateIAMPolicies(principal, tt.action, tt.resource)
+			if result.Decision != tt.wantDecision {
+				t.Errorf("Decision = %s, want %s (reason: %s)", result.Decision, tt.wantDecision, result.Reason)
+			}
+			if result.IsConditional() != tt.wantConditional {
+				t.Errorf("IsConditional() = %v, want %v", result.IsConditional(), tt.wantConditional)
+			}
+			if tt.wantDecidedBy != nil {
+				if len(result.DecidedBy) != len(tt.wantDecidedBy) {
+					t.Fatalf("DecidedBy = %v, want %v", result.DecidedBy, tt.wantDecidedBy)
+				}
+				for i, match := range result.DecidedBy {
+					if match.String() != tt.wantDecidedBy[i] {
+						t.Errorf("DecidedBy[%d] = %s, want %s", i, match.String(), tt.wantDecidedBy[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParseIAMPrincipal(t *testing.T) {
+	tests := []struct {
+		principal string
+		wantType  string
+		wantName  string
+	}{
+		{"MyRole", "", "MyRole"},
+		{"role/MyRole", IAMObjectTypeRole, "MyRole"},
+		{"user/alice", IAMObjectTypeUser, "alice"},
+		{"arn:aws:iam::123456789012:role/service/MyRole", IAMObjectTypeRole, "MyRole"},
+		{"arn:aws:iam::123456789012:user/alice", IAMObjectTypeUser, "alice"},
+	}
+	for _, tt := range tests {
+		gotType, gotName := parseIAMPrincipal(tt.principal)
+		if gotType != tt.wantType || gotName != tt.wantName {
+			t.Errorf("parseIAMPrincipal(%q) = (%q, %q), want (%q, %q)", tt.principal, gotType, gotName, tt.wantType, tt.wantName)
+		}
+	}
+}
+
+func TestGetUserPrincipalPolicies(t *testing.T) {
+	user := IAMUser{
+		Name:                  "alice",
+		InlinePolicies:        map[string]string{"inline": `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`},
+		AttachedGroupPolicies: map[string]string{"group-attached": `{"Statement":[{"Effect":"Deny","Action":"s3:*","Resource":"*"}]}`},
+	}
+	principal, err := getUserPrincipalPolicies(user, &mockIAMClient{})
+	if err != nil {
+		t.Fatalf("getUserPrincipalPolicies() error = %v", err)
+	}
+	if len(principal.Policies) != 2 {
+		t.Fatalf("got %d policies, want 2", len(principal.Policies))
+	}
+	if principal.Policies[1].Type != IAMPolicyTypeGroupAttached {
+		t.Errorf("group policy type = %s, want %s", principal.Policies[1].Type, IAMPolicyTypeGroupAttached)
+	}
+	if principal.PermissionsBoundary != nil {
+		t.Errorf("expected no permissions boundary")
+	}
+	result := EvaluateIAMPolicies(principal, "s3:GetObject", "arn:aws:s3:::bucket/key")
+	if result.Decision != IAMDecisionExplicitDeny {
+		t.Errorf("Decision = %s, want %s", result.Decision, IAMDecisionExplicitDeny)
+	}
+}
@@ -22,6 +22,7 @@ type mockIAMClient struct {
 	attachedGrpPol  map[string][]types.AttachedPolicy // groupname -> attached policies
 	groupsForUser   map[string][]types.Group          // username -> groups
 	usersInGroup    map[string][]types.User           // groupname -> users
+	roles           []types.Role                      // roles, with their RoleLastUsed returned by GetRole
 	pageSize        int                               // items per page for simulating truncation
 }
 
@@ -307,9 +308,14 @@ func (m *mockIAMClient) GetPolicyVersion(_ context.Context, _ *iam.GetPolicyVers
 	}, nil
 }
 
-// ListRoles stub for interface compliance.
+// ListRoles returns the roles without their RoleLastUsed, as the real API does.
 func (m *mockIAMClient) ListRoles(_ context.Context, input *iam.ListRolesInput, _ ...func(*iam.Options)) (*iam.ListRolesOutput, error) {
-	return &iam.ListRolesOutput{}, nil
+	output := &iam.ListRolesOutput{}
+	for _, role := range m.roles {
+		role.RoleLastUsed = nil
+		output.Roles = append(output.Roles, role)
+	}
+	return output, nil
 }
 
 // ListRolePolicies stub for interface compliance.
@@ -332,6 +338,21 @@ func (m *mockIAMClient) ListAccountAliases(_ context.Context, _ *iam.ListAccount
 	return &iam.ListAccountAliasesOutput{}, nil
 }
 
+// GetRole returns the matching role from the mock, or an empty role.
+func (m *mockIAMClient) GetRole(_ context.Context, input *iam.GetRoleInput, _ ...func(*iam.Options)) (*iam.GetRoleOutput, error) {
+	for _, role := range m.roles {
+		if aws.ToString(role.RoleName) == aws.ToString(input.RoleName) {
+			return &iam.GetRoleOutput{Role: &role}, nil
+		}
+	}
+	return &iam.GetRoleOutput{Role: &types.Role{RoleName: input.RoleName}}, nil
+}
+
+// GetUser stub for interface compliance.
+func (m *mockIAMClient) GetUser(_ context.Context, input *iam.GetUserInput, _ ...func(*iam.Options)) (*iam.GetUserOutput, error) {
+	return &iam.GetUserOutput{User: &types.User{UserName: input.UserName}}, nil
+}
+
 // GetRolePolicy stub for interface compliance.
 func (m *mockIAMClient) GetRolePolicy(_ context.Context, _ *iam.GetRolePolicyInput, _ ...func(*iam.Options)) (*iam.GetRolePolicyOutput, error) {
 	return &iam.GetRolePolicyOutput{}, nil
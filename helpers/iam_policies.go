@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAMPolicyAPI is the IAM API surface needed to list the customer managed
+// policies and the principals they're attached to
+type IAMPolicyAPI interface {
+	iam.ListPoliciesAPIClient
+	iam.ListEntitiesForPolicyAPIClient
+}
+
+// IAMManagedPolicy is a customer managed policy and the principals it is attached to
+type IAMManagedPolicy struct {
+	Name               string
+	Arn                string
+	Path               string
+	DefaultVersion     string
+	AttachmentCount    int32
+	BoundaryUsageCount int32
+	Created            time.Time
+	Updated            time.Time
+	Users              []string
+	Groups             []string
+	Roles              []string
+}
+
+// IsUnattached returns whether the policy isn't attached to any principal
+// and isn't used as a permissions boundary
+func (policy IAMManagedPolicy) IsUnattached() bool {
+	return policy.AttachmentCount == 0 && policy.BoundaryUsageCount == 0
+}
+
+// GetCustomerManagedPolicies returns all customer managed policies in the
+// account with the users, groups, and roles they're attached to. The
+// attached entities are only looked up for policies that have attachments.
+func GetCustomerManagedPolicies(svc IAMPolicyAPI) []IAMManagedPolicy {
+	var result []IAMManagedPolicy
+	paginator := iam.NewListPoliciesPaginator(svc, &iam.ListPoliciesInput{Scope: types.PolicyScopeTypeLocal})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, policy := range page.Policies {
+			managed := IAMManagedPolicy{
+				Name:               aws.ToString(policy.PolicyName),
+				Arn:                aws.ToString(policy.Arn),
+				Path:               aws.ToString(policy.Path),
+				DefaultVersion:     aws.ToString(policy.DefaultVersionId),
+				AttachmentCount:    aws.ToInt32(policy.AttachmentCount),
+				BoundaryUsageCount: aws.ToInt32(policy.PermissionsBoundaryUsageCount),
+				Created:            aws.ToTime(policy.CreateDate),
+				Updated:            aws.ToTime(policy.UpdateDate),
+			}
+			if managed.AttachmentCount > 0 {
+				addPolicyEntities(&managed, svc)
+			}
+			result = append(result, managed)
+		}
+	}
+	return result
+}
+
+func addPolicyEntities(policy *IAMManagedPolicy, svc iam.ListEntitiesForPolicyAPIClient) {
+	paginator := iam.NewListEntitiesForPolicyPaginator(svc, &iam.ListEntitiesForPolicyInput{PolicyArn: aws.String(policy.Arn)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, user := range page.PolicyUsers {
+			policy.Users = append(policy.Users, aws.ToString(user.UserName))
+		}
+		for _, group := range page.PolicyGroups {
+			policy.Groups = append(policy.Groups, aws.ToString(group.GroupName))
+		}
+		for _, role := range page.PolicyRoles {
+			policy.Roles = append(policy.Roles, aws.ToString(role.RoleName))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+type mockIAMPolicyClient struct {
+	policies    []types.Policy
+	entities    map[string]*iam.ListEntitiesForPolicyOutput
+	scopes      []types.PolicyScopeType
+	entityCalls int
+	pageSize    int
+	policyCalls int
+}
+
+func (m *mockIAMPolicyClient) ListPolicies(_ context.Context, input *iam.ListPoliciesInput, _ ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	m.policyCalls++
+	m.scopes = append(m.scopes, input.Scope)
+	start := 0
+	if input.Marker != nil {
+		fmt.Sscanf(*input.Marker, "%d", &start)
+	}
+	end := min(start+m.pageSize, len(m.policies))
+	output := &iam.ListPoliciesOutput{Policies: m.policies[start:end], IsTruncated: end < len(m.policies)}
+	if output.IsTruncated {
+		output.Marker = aws.String(fmt.Sprintf("%d", end))
+	}
+	return output, nil
+}
+
+func (m *mockIAMPolicyClient) ListEntitiesForPolicy(_ context.Context, input *iam.ListEntitiesForPolicyInput, _ ...func(*iam.Options)) (*iam.ListEntitiesForPolicyOutput, error) {
+	m.entityCalls++
+	if output, ok := m.entities[aws.ToString(input.PolicyArn)]; ok {
+		return output, nil
+	}
+	return &iam.ListEntitiesForPolicyOutput{}, nil
+}
+
+func TestGetCustomerManagedPolicies(t *testing.T) {
+	policy := func(name string, attachments int32, boundaries int32) types.Policy {
+		return types.Policy{
+			PolicyName:                    aws.String(name),
+			Arn:                           aws.String("arn:aws:iam::123456789012:policy/" + name),
+			Path:                          aws.String("/"),
+			DefaultVersionId:              aws.String("v2"),
+			AttachmentCount:               aws.Int32(attachments),
+			PermissionsBoundaryUsageCount: aws.Int32(boundaries),
+		}
+	}
+	mock := &mockIAMPolicyClient{
+		policies: []types.Policy{policy("used", 3, 0), policy("unused", 0, 0), policy("boundary", 0, 1)},
+		entities: map[string]*iam.ListEntitiesForPolicyOutput{
+			"arn:aws:iam::123456789012:policy/used": {
+				PolicyUsers:  []types.PolicyUser{{UserName: aws.String("alice")}},
+				PolicyGroups: []types.PolicyGroup{{GroupName: aws.String("admins")}},
+				PolicyRoles:  []types.PolicyRole{{RoleName: aws.String("deploy")}},
+			},
+		},
+		pageSize: 2,
+	}
+	result := GetCustomerManagedPolicies(mock)
+	if len(result) != 3 {
+		t.Fatalf("got %d policies, want 3", len(result))
+	}
+	if mock.policyCalls != 2 {
+		t.Errorf("ListPolicies called %d times, want 2", mock.policyCalls)
+	}
+	for _, scope := range mock.scopes {
+		if scope != types.PolicyScopeTypeLocal {
+			t.Errorf("ListPolicies scope = %s, want %s", scope, types.PolicyScopeTypeLocal)
+		}
+	}
+	if mock.entityCalls != 1 {
+		t.Errorf("ListEntitiesForPolicy called %d times, want 1", mock.entityCalls)
+	}
+	used := result[0]
+	if !reflect.DeepEqual(used.Users, []string{"alice"}) || !reflect.DeepEqual(used.Groups, []string{"admins"}) || !reflect.DeepEqual(used.Roles, []string{"deploy"}) {
+		t.Errorf("attached entities = %v %v %v", used.Users, used.Groups, used.Roles)
+	}
+	if used.IsUnattached() || !result[1].IsUnattached() || result[2].IsUnattached() {
+		t.Errorf("IsUnattached() = %v %v %v, want false true false", used.IsUnattached(), result[1].IsUnattached(), result[2].IsUnattached())
+	}
+}
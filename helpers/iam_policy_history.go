@@ -0,0 +1,189 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAMPolicyVersionLimit is the maximum number of versions a managed policy can have
+const IAMPolicyVersionLimit = 5
+
+// Changes between policy versions
+const (
+	IAMPolicyChangeAdded   = "Added"
+	IAMPolicyChangeRemoved = "Removed"
+)
+
+// IAMPolicyVersionAPI is the IAM API surface needed to retrieve the versions
+// of customer managed policies
+type IAMPolicyVersionAPI interface {
+	iam.ListPoliciesAPIClient
+	iam.ListPolicyVersionsAPIClient
+	GetPolicyVersion(ctx context.Context, params *iam.GetPolicyVersionInput, optFns ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error)
+}
+
+// IAMPolicyChange is a statement that was added or removed in a policy version
+type IAMPolicyChange struct {
+	Change    string
+	Statement string
+}
+
+// IAMPolicyVersion is a single version of a managed policy and the changes
+// compared to the version before it
+type IAMPolicyVersion struct {
+	VersionID string
+	Created   time.Time
+	IsDefault bool
+	Document  IAMPolicyDocument
+	Changes   []IAMPolicyChange
+}
+
+// IAMPolicyHistory is a managed policy with its versions, oldest first
+type IAMPolicyHistory struct {
+	Name     string
+	Arn      string
+	Versions []IAMPolicyVersion
+}
+
+// AtVersionLimit returns whether the policy has the maximum number of
+// versions, meaning the next change has to delete a version first
+func (history IAMPolicyHistory) AtVersionLimit() bool {
+	return len(history.Versions) >= IAMPolicyVersionLimit
+}
+
+// GetPolicyHistory retrieves all versions of the managed policy and the
+// statement-level changes between consecutive versions. The oldest version
+// has no changes, as the version before it may have been deleted.
+func GetPolicyHistory(policyArn string, svc IAMPolicyVersionAPI) (IAMPolicyHistory, error) {
+	history := IAMPolicyHistory{Name: policyArn[strings.LastIndex(policyArn, "/")+1:], Arn: policyArn}
+	var versions []types.PolicyVersion
+	paginator := iam.NewListPolicyVersionsPaginator(svc, &iam.ListPolicyVersionsInput{PolicyArn: aws.String(policyArn)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return history, fmt.Errorf("failed to list versions of policy %s: %w", policyArn, err)
+		}
+		versions = append(versions, page.Versions...)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return aws.ToTime(versions[i].CreateDate).Before(aws.ToTime(versions[j].CreateDate))
+	})
+	for _, version := range versions {
+		resp, err := svc.GetPolicyVersion(context.TODO(), &iam.GetPolicyVersionInput{PolicyArn: aws.String(policyArn), VersionId: version.VersionId})
+		if err != nil {
+			return history, fmt.Errorf("failed to get version %s of policy %s: %w", aws.ToString(version.VersionId), policyArn, err)
+		}
+		document, err := url.QueryUnescape(aws.ToString(resp.PolicyVersion.Document))
+		if err != nil {
+			return history, fmt.Errorf("failed to decode version %s of policy %s: %w", aws.ToString(version.VersionId), policyArn, err)
+		}
+		parsed, err := ParseIAMPolicyDocument(history.Name, IAMPolicyTypeAttached, document)
+		if err != nil {
+			return history, err
+		}
+		history.Versions = append(history.Versions, IAMPolicyVersion{
+			VersionID: aws.ToString(version.VersionId),
+			Created:   aws.ToTime(version.CreateDate),
+			IsDefault: version.IsDefaultVersion,
+			Document:  parsed,
+		})
+	}
+	for i := 1; i < len(history.Versions); i++ {
+		history.Versions[i].Changes = DiffPolicyStatements(history.Versions[i-1].Document, history.Versions[i].Document)
+	}
+	return history, nil
+}
+
+// GetCustomerManagedPolicyHistories retrieves the version history of every
+// customer managed policy in the account
+func GetCustomerManagedPolicyHistories(svc IAMPolicyVersionAPI) ([]IAMPolicyHistory, error) {
+	var result []IAMPolicyHistory
+	paginator := iam.NewListPoliciesPaginator(svc, &iam.ListPoliciesInput{Scope: types.PolicyScopeTypeLocal})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return result, fmt.Errorf("failed to list policies: %w", err)
+		}
+		for _, policy := range page.Policies {
+			history, err := GetPolicyHistory(aws.ToString(policy.Arn), svc)
+			if err != nil {
+				return result, err
+			}
+			result = append(result, history)
+		}
+	}
+	return result, nil
+}
+
+// DiffPolicyStatements returns the statements that were removed from the
+// previous document and added in the current one. Statements are compared
+// after normalising their actions and resources, so reordering actions or
+// turning a single action into a list isn't reported as a change.
+func DiffPolicyStatements(previous IAMPolicyDocument, current IAMPolicyDocument) []IAMPolicyChange {
+	previousStatements := canonicalStatements(previous)
+	currentStatements := canonicalStatements(current)
+	var result []IAMPolicyChange
+	for _, statement := range previousStatements {
+		if !slices.Contains(currentStatements, statement) {
+			result = append(result, IAMPolicyChange{Change: IAMPolicyChangeRemoved, Statement: statement})
+		}
+	}
+	for _, statement := range currentStatements {
+		if !slices.Contains(previousStatements, statement) {
+			result = append(result, IAMPolicyChange{Change: IAMPolicyChangeAdded, Statement: statement})
+		}
+	}
+	return result
+}
+
+// canonicalStatements returns the statements of the document as compact
+// JSON with sorted actions and resources. Only the elements that are set
+// are included.
+func canonicalStatements(document IAMPolicyDocument) []string {
+	result := make([]string, 0, len(document.Statement))
+	for _, statement := range document.Statement {
+		canonical := map[string]any{"Effect": statement.Effect}
+		if statement.Sid != "" {
+			canonical["Sid"] = statement.Sid
+		}
+		if len(statement.Principal) > 0 {
+			canonical["Principal"] = statement.Principal
+		}
+		if statement.Condition != nil {
+			canonical["Condition"] = statement.Condition
+		}
+		for element, value := range map[string]any{
+			"Action":      statement.Action,
+			"NotAction":   statement.NotAction,
+			"Resource":    statement.Resource,
+			"NotResource": statement.NotResource,
+		} {
+			if value != nil {
+				canonical[element] = sortedValues(value)
+			}
+		}
+		// json.Marshal sorts map keys, so equal statements give equal strings
+		encoded, err := json.Marshal(canonical)
+		if err != nil {
+			encoded = fmt.Appendf(nil, "%v", canonical)
+		}
+		result = append(result, string(encoded))
+	}
+	return result
+}
+
+func sortedValues(value any) []string {
+	values := slices.Clone(normalizeActions(value))
+	slices.Sort(values)
+	return values
+}
@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+type mockIAMPolicyVersionClient struct {
+	policies  []types.Policy
+	versions  map[string][]types.PolicyVersion
+	documents map[string]string
+}
+
+func (m *mockIAMPolicyVersionClient) ListPolicies(_ context.Context, _ *iam.ListPoliciesInput, _ ...func(*iam.Options)) (*iam.ListPoliciesOutput, error) {
+	return &iam.ListPoliciesOutput{Policies: m.policies}, nil
+}
+
+func (m *mockIAMPolicyVersionClient) ListPolicyVersions(_ context.Context, input *iam.ListPolicyVersionsInput, _ ...func(*iam.Options)) (*iam.ListPolicyVersionsOutput, error) {
+	return &iam.ListPolicyVersionsOutput{Versions: m.versions[aws.ToString(input.PolicyArn)]}, nil
+}
+
+func (m *mockIAMPolicyVersionClient) GetPolicyVersion(_ context.Context, input *iam.GetPolicyVersionInput, _ ...func(*iam.Options)) (*iam.GetPolicyVersionOutput, error) {
+	document := m.documents[aws.ToString(input.PolicyArn)+"/"+aws.ToString(input.VersionId)]
+	return &iam.GetPolicyVersionOutput{PolicyVersion: &types.PolicyVersion{
+		VersionId: input.VersionId,
+		Document:  aws.String(url.QueryEscape(document)),
+	}}, nil
+}
+
+func TestGetPolicyHistory(t *testing.T) {
+	arn := "arn:aws:iam::123456789012:policy/deploy"
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockIAMPolicyVersionClient{
+		// Returned newest first, like the API does
+		versions: map[string][]types.PolicyVersion{arn: {
+			{VersionId: aws.String("v3"), CreateDate: aws.Time(start.AddDate(0, 2, 0)), IsDefaultVersion: true},
+			{VersionId: aws.String("v2"), CreateDate: aws.Time(start.AddDate(0, 1, 0))},
+			{VersionId: aws.String("v1"), CreateDate: aws.Time(start)},
+		}},
+		documents: map[string]string{
+			arn + "/v1": `{"Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`,
+			arn + "/v2": `{"Statement":[{"Effect":"Allow","Action":["s3:GetObject"],"Resource":"*"},{"Effect":"Allow","Action":["sqs:SendMessage","sqs:ReceiveMessage"],"Resource":"*"}]}`,
+			arn + "/v3": `{"Statement":[{"Effect":"Allow","Action":["sqs:ReceiveMessage","sqs:SendMessage"],"Resource":"*"}]}`,
+		},
+	}
+	history, err := GetPolicyHistory(arn, mock)
+	if err != nil {
+		t.Fatalf("GetPolicyHistory() error = %v", err)
+	}
+	if history.Name != "deploy" || len(history.Versions) != 3 {
+		t.Fatalf("GetPolicyHistory() = %+v", history)
+	}
+	if history.Versions[0].VersionID != "v1" || !history.Versions[2].IsDefault {
+		t.Errorf("versions aren't sorted oldest first: %s, %s, %s", history.Versions[0].VersionID, history.Versions[1].VersionID, history.Versions[2].VersionID)
+	}
+	if len(history.Versions[0].Changes) != 0 {
+		t.Errorf("oldest version has changes: %v", history.Versions[0].Changes)
+	}
+	want := []IAMPolicyChange{{IAMPolicyChangeAdded, `{"Action":["sqs:ReceiveMessage","sqs:SendMessage"],"Effect":"Allow","Resource":["*"]}`}}
+	if got := history.Versions[1].Changes; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("v2 changes = %v, want %v", got, want)
+	}
+	want = []IAMPolicyChange{{IAMPolicyChangeRemoved, `{"Action":["s3:GetObject"],"Effect":"Allow","Resource":["*"]}`}}
+	if got := history.Versions[2].Changes; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("v3 changes = %v, want %v", got, want)
+	}
+	if history.AtVersionLimit() {
+		t.Errorf("AtVersionLimit() = true for 3 versions")
+	}
+}
+
+func TestGetCustomerManagedPolicyHistories_VersionLimit(t *testing.T) {
+	arn := "arn:aws:iam::123456789012:policy/full"
+	mock := &mockIAMPolicyVersionClient{
+		policies:  []types.Policy{{Arn: aws.String(arn)}},
+		versions:  map[string][]types.PolicyVersion{},
+		documents: map[string]string{},
+	}
+	for _, id := range []string{"v1", "v2", "v3", "v4", "v5"} {
+		mock.versions[arn] = append(mock.versions[arn], types.PolicyVersion{VersionId: aws.String(id), CreateDate: aws.Time(time.Now())})
+		mock.documents[arn+"/"+id] = `{"Statement":[{"Effect":"Allow","Action":"s3:ListBucket","Resource":"*"}]}`
+	}
+	histories, err := GetCustomerManagedPolicyHistories(mock)
+	if err != nil {
+		t.Fatalf("GetCustomerManagedPolicyHistories() error = %v", err)
+	}
+	if len(histories) != 1 || !histories[0].AtVersionLimit() {
+		t.Errorf("expected one policy at the version limit, got %+v", histories)
+	}
+}
@@ -0,0 +1,271 @@
+package helpers
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Risks found in IAM policies
+const (
+	IAMRiskAdmin               = "Admin-equivalent"
+	IAMRiskPassRole            = "PassRole on all resources"
+	IAMRiskPrivilegeEscalation = "Privilege escalation"
+	IAMRiskServiceWildcard     = "Full service access"
+)
+
+var serviceWildcardRegex = regexp.MustCompile(`^[a-zA-Z0-9-]+:\*$`)
+
+// privilegeEscalationPath is a set of actions that together allow a
+// principal to gain more permissions than it was granted. For paths that
+// only escalate when used on other principals, statements that are scoped to
+// the principal itself don't count.
+type privilegeEscalationPath struct {
+	Description string
+	Actions     []string
+	OthersOnly  bool
+}
+
+// privilegeEscalationPaths are the known ways to escalate privileges through
+// IAM and services that can run code with a passed role
+var privilegeEscalationPaths = []privilegeEscalationPath{
+	{"Create a new default version of a managed policy", []string{"iam:CreatePolicyVersion"}, false},
+	{"Change the default version of a managed policy", []string{"iam:SetDefaultPolicyVersion"}, false},
+	{"Attach any managed policy to a user", []string{"iam:AttachUserPolicy"}, false},
+	{"Attach any managed policy to a group", []string{"iam:AttachGroupPolicy"}, false},
+	{"Attach any managed policy to a role", []string{"iam:AttachRolePolicy"}, false},
+	{"Add an inline policy to a user", []string{"iam:PutUserPolicy"}, false},
+	{"Add an inline policy to a group", []string{"iam:PutGroupPolicy"}, false},
+	{"Add an inline policy to a role", []string{"iam:PutRolePolicy"}, false},
+	{"Add a user to a more privileged group", []string{"iam:AddUserToGroup"}, false},
+	{"Create access keys for another user", []string{"iam:CreateAccessKey"}, true},
+	{"Create a console password for another user", []string{"iam:CreateLoginProfile"}, true},
+	{"Change the console password of another user", []string{"iam:UpdateLoginProfile"}, true},
+	{"Change the trust policy of a role and assume it", []string{"iam:UpdateAssumeRolePolicy", "sts:AssumeRole"}, false},
+	{"Launch an EC2 instance with a privileged role", []string{"iam:PassRole", "ec2:RunInstances"}, false},
+	{"Create and invoke a Lambda function with a privileged role", []string{"iam:PassRole", "lambda:CreateFunction", "lambda:InvokeFunction"}, false},
+	{"Replace the code of a Lambda function with a privileged role", []string{"iam:PassRole", "lambda:UpdateFunctionCode"}, false},
+	{"Create a Glue development endpoint with a privileged role", []string{"iam:PassRole", "glue:CreateDevEndpoint"}, false},
+	{"Create a CloudFormation stack with a privileged role", []string{"iam:PassRole", "cloudformation:CreateStack"}, false},
+	{"Create a Data Pipeline with a privileged role", []string{"iam:PassRole", "datapipeline:CreatePipeline", "datapipeline:PutPipelineDefinition"}, false},
+}
+
+// IAMRiskFinding is a risky permission granted to a role, user, or group
+type IAMRiskFinding struct {
+	Principal     string
+	PrincipalType string
+	Risk          string
+	Description   string
+	Statements    []IAMStatementMatch
+}
+
+// GetIAMPrincipals collects the identity policies of all roles, users, and
+// groups in the account. The policies of users include the ones inherited
+// from their groups.
+func GetIAMPrincipals(svc IAMClient) ([]IAMPrincipalPolicies, error) {
+	var result []IAMPrincipalPolicies
+	roles, _ := GetRolesAndPolicies(true, svc)
+	for _, role := range roles {
+		result = append(result, rolePrincipalPolicies(role))
+	}
+	for _, user := range GetUserDetails(svc) {
+		principal, err := userPrincipalPolicies(user)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, principal)
+	}
+	for _, group := range GetGroupDetails(svc) {
+		principal, err := groupPrincipalPolicies(group)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, principal)
+	}
+	return result, nil
+}
+
+// FindRiskyPermissions scans the policies of the principals for
+// admin-equivalent statements, iam:PassRole on all resources, services
+// granted all actions, and known privilege escalation paths.
+//
+// The statement checks skip the group policies of users, as these are
+// reported for the group itself. Privilege escalation paths take all of a
+// principal's policies into account, as the actions can be spread over
+// multiple policies. An action counts as granted when any Allow statement
+// matches it, regardless of its resources, unless a statement explicitly
+// denies it for all resources. The exception are the paths that create or
+// change the credentials of other users, where statements scoped to the user
+// itself (such as user/${aws:username}) are ignored. Principals that are
+// admin-equivalent aren't checked for escalation paths.
+func FindRiskyPermissions(principals []IAMPrincipalPolicies) []IAMRiskFinding {
+	var result []IAMRiskFinding
+	for _, principal := range principals {
+		for _, policy := range principal.Policies {
+			if policy.Type == IAMPolicyTypeGroupInline || policy.Type == IAMPolicyTypeGroupAttached {
+				continue
+			}
+			for index, statement := range policy.Statement {
+				result = append(result, statementRisks(principal, policy, index, statement)...)
+			}
+		}
+		if isAdminEquivalent(principal) {
+			continue
+		}
+		result = append(result, privilegeEscalationRisks(principal)...)
+	}
+	return result
+}
+
+func statementRisks(principal IAMPrincipalPolicies, policy IAMPolicyDocument, index int, statement IAMPolicyDocumentStatement) []IAMRiskFinding {
+	if !strings.EqualFold(statement.Effect, "Allow") {
+		return nil
+	}
+	match := IAMStatementMatch{PolicyName: policy.Name, PolicyType: policy.Type, Index: index, Statement: statement}
+	finding := func(risk string, description string) IAMRiskFinding {
+		return IAMRiskFinding{
+			Principal:     principal.Name,
+			PrincipalType: principal.Type,
+			Risk:          risk,
+			Description:   description,
+			Statements:    []IAMStatementMatch{match},
+		}
+	}
+	if statementIsAdmin(statement) {
+		return []IAMRiskFinding{finding(IAMRiskAdmin, "Allows all actions on all resources")}
+	}
+	var result []IAMRiskFinding
+	if statementMatches(statement, "iam:PassRole", "*") {
+		result = append(result, finding(IAMRiskPassRole, "Allows passing any role to a service"))
+	}
+	var services []string
+	for _, action := range normalizeActions(statement.Action) {
+		if serviceWildcardRegex.MatchString(action) {
+			services = append(services, strings.TrimSuffix(action, ":*"))
+		}
+	}
+	if len(services) > 0 {
+		result = append(result, finding(IAMRiskServiceWildcard, "Allows all actions for "+strings.Join(services, ", ")))
+	}
+	return result
+}
+
+// statementIsAdmin returns whether the statement allows all actions on all resources
+func statementIsAdmin(statement IAMPolicyDocumentStatement) bool {
+	if !strings.EqualFold(statement.Effect, "Allow") || statement.Resource == nil {
+		return false
+	}
+	actions := normalizeActions(statement.Action)
+	return (slices.Contains(actions, "*") || slices.Contains(actions, "*:*")) && slices.Contains(normalizeActions(statement.Resource), "*")
+}
+
+// isAdminEquivalent returns whether any of the principal's policies,
+// including inherited group policies, allows all actions on all resources
+func isAdminEquivalent(principal IAMPrincipalPolicies) bool {
+	for _, policy := range principal.Policies {
+		if slices.ContainsFunc(policy.Statement, statementIsAdmin) {
+			return true
+		}
+	}
+	return false
+}
+
+func privilegeEscalationRisks(principal IAMPrincipalPolicies) []IAMRiskFinding {
+	var result []IAMRiskFinding
+	for _, path := range privilegeEscalationPaths {
+		var statements []IAMStatementMatch
+		for _, action := range path.Actions {
+			match, ok := grantingStatement(principal, action, path.OthersOnly)
+			if !ok {
+				statements = nil
+				break
+			}
+			statements = append(statements, match)
+		}
+		if statements == nil {
+			continue
+		}
+		result = append(result, IAMRiskFinding{
+			Principal:     principal.Name,
+			PrincipalType: principal.Type,
+			Risk:          IAMRiskPrivilegeEscalation,
+			Description:   fmt.Sprintf("%s (%s)", path.Description, strings.Join(path.Actions, " + ")),
+			Statements:    statements,
+		})
+	}
+	return result
+}
+
+// grantingStatement returns the first Allow statement that grants the action
+// for any resource, unless the action is explicitly denied for all resources.
+// With othersOnly, statements scoped to the principal itself are skipped.
+func grantingStatement(principal IAMPrincipalPolicies, action string, othersOnly bool) (IAMStatementMatch, bool) {
+	if EvaluateIAMPolicies(principal, action, "*").Decision == IAMDecisionExplicitDeny {
+		return IAMStatementMatch{}, false
+	}
+	for _, policy := range principal.Policies {
+		for index, statement := range policy.Statement {
+			if !strings.EqualFold(statement.Effect, "Allow") || !statementMatchesAction(statement, action) {
+				continue
+			}
+			if othersOnly && statementIsSelfScoped(statement, principal) {
+				continue
+			}
+			return IAMStatementMatch{PolicyName: policy.Name, PolicyType: policy.Type, Index: index, Statement: statement}, true
+		}
+	}
+	return IAMStatementMatch{}, false
+}
+
+// statementMatchesAction returns whether the Action or NotAction of the
+// statement applies to the action
+func statementMatchesAction(statement IAMPolicyDocumentStatement, action string) bool {
+	switch {
+	case statement.Action != nil:
+		return anyWildcardMatch(normalizeActions(statement.Action), action, true)
+	case statement.NotAction != nil:
+		return !anyWildcardMatch(normalizeActions(statement.NotAction), action, true)
+	}
+	return false
+}
+
+// statementIsSelfScoped returns whether all resources of the statement are
+// limited to the principal itself
+func statementIsSelfScoped(statement IAMPolicyDocumentStatement, principal IAMPrincipalPolicies) bool {
+	if statement.Resource == nil || statement.NotResource != nil {
+		return false
+	}
+	resources := normalizeActions(statement.Resource)
+	if len(resources) == 0 {
+		return false
+	}
+	for _, resource := range resources {
+		if !resourceIsSelfScoped(resource, principal) {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceIsSelfScoped returns whether the resource only matches the user
+// making the request, either through the ${aws:username} policy variable or
+// because it's the ARN of the user itself. Wildcards in the resource path
+// make it reach beyond the user.
+func resourceIsSelfScoped(resource string, principal IAMPrincipalPolicies) bool {
+	parts := strings.SplitN(resource, ":", 6)
+	if len(parts) != 6 || parts[2] != "iam" {
+		return false
+	}
+	path := parts[5]
+	if strings.ContainsAny(strings.ReplaceAll(path, "${aws:username}", ""), "*?") {
+		return false
+	}
+	if !strings.HasPrefix(path, "user/") {
+		return false
+	}
+	if strings.HasSuffix(path, "/${aws:username}") {
+		return true
+	}
+	return principal.Type == IAMObjectTypeUser && strings.HasSuffix(path, "/"+principal.Name)
+}
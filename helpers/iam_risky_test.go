@@ -0,0 +1,141 @@
+package helpers
+
+import (
+	"slices"
+	"testing"
+)
+
+func riskyPrincipal(t *testing.T, name string, principaltype string, documents map[string]string) IAMPrincipalPolicies {
+	t.Helper()
+	principal := IAMPrincipalPolicies{Name: name, Type: principaltype}
+	for _, policyname := range sortedKeys(documents) {
+		principal.Policies = append(principal.Policies, mustParsePolicy(t, policyname, IAMPolicyTypeAttached, documents[policyname]))
+	}
+	return principal
+}
+
+func riskSummary(findings []IAMRiskFinding) map[string][]string {
+	result := make(map[string][]string)
+	for _, finding := range findings {
+		result[finding.Principal] = append(result[finding.Principal], finding.Risk+": "+finding.Description)
+	}
+	return result
+}
+
+func TestFindRiskyPermissions(t *testing.T) {
+	principals := []IAMPrincipalPolicies{
+		riskyPrincipal(t, "Admin", IAMObjectTypeRole, map[string]string{
+			"AdministratorAccess": `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			"IAMFull":             `{"Statement":[{"Effect":"Allow","Action":"iam:AttachRolePolicy","Resource":"*"}]}`,
+		}),
+		riskyPrincipal(t, "Deployer", IAMObjectTypeRole, map[string]string{
+			"Deploy": `{"Statement":[{"Effect":"Allow","Action":["lambda:UpdateFunctionCode","s3:*"],"Resource":"*"}]}`,
+			"Pass":   `{"Statement":[{"Effect":"Allow","Action":"iam:PassRole","Resource":"*"}]}`,
+		}),
+		riskyPrincipal(t, "ScopedPass", IAMObjectTypeRole, map[string]string{
+			"Pass": `{"Statement":[{"Effect":"Allow","Action":"iam:PassRole","Resource":"arn:aws:iam::123456789012:role/app-*"},{"Effect":"Allow","Action":"ec2:RunInstances","Resource":"*"}]}`,
+		}),
+		riskyPrincipal(t, "Denied", IAMObjectTypeUser, map[string]string{
+			"Policies": `{"Statement":[{"Effect":"Allow","Action":"iam:CreatePolicyVersion","Resource":"*"},{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+		}),
+		riskyPrincipal(t, "ReadOnly", IAMObjectTypeGroup, map[string]string{
+			"Read": `{"Statement":[{"Effect":"Allow","Action":["s3:Get*","s3:List*"],"Resource":"*"}]}`,
+		}),
+	}
+	got := riskSummary(FindRiskyPermissions(principals))
+	want := map[string][]string{
+		"Admin": {
+			"Admin-equivalent: Allows all actions on all resources",
+		},
+		"Deployer": {
+			"Full service access: Allows all actions for s3",
+			"PassRole on all resources: Allows passing any role to a service",
+			"Privilege escalation: Replace the code of a Lambda function with a privileged role (iam:PassRole + lambda:UpdateFunctionCode)",
+		},
+		"ScopedPass": {
+			"Privilege escalation: Launch an EC2 instance with a privileged role (iam:PassRole + ec2:RunInstances)",
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindRiskyPermissions() = %v, want %v", got, want)
+	}
+	for principal, risks := range want {
+		if len(got[principal]) != len(risks) {
+			t.Errorf("%s findings = %v, want %v", principal, got[principal], risks)
+			continue
+		}
+		for i, risk := range risks {
+			if got[principal][i] != risk {
+				t.Errorf("%s finding %d = %s, want %s", principal, i, got[principal][i], risk)
+			}
+		}
+	}
+}
+
+func TestFindRiskyPermissions_StatementDetails(t *testing.T) {
+	principal := riskyPrincipal(t, "Deployer", IAMObjectTypeRole, map[string]string{
+		"Deploy": `{"Statement":[{"Effect":"Allow","Action":"lambda:UpdateFunctionCode","Resource":"*"}]}`,
+		"Pass":   `{"Statement":[{"Sid":"PassAll","Effect":"Allow","Action":"iam:PassRole","Resource":"*"}]}`,
+	})
+	for _, finding := range FindRiskyPermissions([]IAMPrincipalPolicies{principal}) {
+		if finding.Risk != IAMRiskPrivilegeEscalation {
+			continue
+		}
+		if len(finding.Statements) != 2 {
+			t.Fatalf("got %d statements, want 2", len(finding.Statements))
+		}
+		if finding.Statements[0].String() != "Pass (Attached Policy) statement 1 (Sid: PassAll)" {
+			t.Errorf("Statements[0] = %s", finding.Statements[0].String())
+		}
+		if finding.Statements[1].PolicyName != "Deploy" {
+			t.Errorf("Statements[1] policy = %s, want Deploy", finding.Statements[1].PolicyName)
+		}
+		return
+	}
+	t.Errorf("expected a privilege escalation finding")
+}
+
+func TestFindRiskyPermissions_SkipsInheritedGroupStatements(t *testing.T) {
+	user := IAMPrincipalPolicies{Name: "alice", Type: IAMObjectTypeUser}
+	user.Policies = append(user.Policies, mustParsePolicy(t, "GroupAdmin", IAMPolicyTypeGroupAttached, `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`))
+	user.Policies = append(user.Policies, mustParsePolicy(t, "Own", IAMPolicyTypeInline, `{"Statement":[{"Effect":"Allow","Action":"iam:CreateAccessKey","Resource":"*"}]}`))
+	if findings := FindRiskyPermissions([]IAMPrincipalPolicies{user}); len(findings) != 0 {
+		t.Errorf("FindRiskyPermissions() = %v, want no findings for a user that is admin through a group", riskSummary(findings))
+	}
+}
+
+func TestFindRiskyPermissions_SelfServiceCredentials(t *testing.T) {
+	selfService := `{"Statement":[{"Effect":"Allow","Action":["iam:CreateAccessKey","iam:CreateLoginProfile","iam:UpdateLoginProfile"],"Resource":"arn:aws:iam::*:user/${aws:username}"}]}`
+	principals := []IAMPrincipalPolicies{
+		riskyPrincipal(t, "SelfService", IAMObjectTypeGroup, map[string]string{"SelfService": selfService}),
+		riskyPrincipal(t, "alice", IAMObjectTypeUser, map[string]string{
+			"Own": `{"Statement":[{"Effect":"Allow","Action":"iam:CreateAccessKey","Resource":"arn:aws:iam::123456789012:user/dev/alice"}]}`,
+		}),
+		riskyPrincipal(t, "bob", IAMObjectTypeUser, map[string]string{
+			"Others":   `{"Statement":[{"Effect":"Allow","Action":"iam:CreateAccessKey","Resource":"arn:aws:iam::123456789012:user/alice"}]}`,
+			"Wildcard": `{"Statement":[{"Effect":"Allow","Action":"iam:UpdateLoginProfile","Resource":"arn:aws:iam::*:user/${aws:username}*"}]}`,
+		}),
+		riskyPrincipal(t, "carol", IAMObjectTypeUser, map[string]string{
+			// Attaching policies to yourself is still an escalation path
+			"OwnPolicies": `{"Statement":[{"Effect":"Allow","Action":"iam:AttachUserPolicy","Resource":"arn:aws:iam::*:user/${aws:username}"}]}`,
+		}),
+	}
+	got := riskSummary(FindRiskyPermissions(principals))
+	want := map[string][]string{
+		"bob": {
+			"Privilege escalation: Create access keys for another user (iam:CreateAccessKey)",
+			"Privilege escalation: Change the console password of another user (iam:UpdateLoginProfile)",
+		},
+		"carol": {
+			"Privilege escalation: Attach any managed policy to a user (iam:AttachUserPolicy)",
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindRiskyPermissions() = %v, want %v", got, want)
+	}
+	for principal, risks := range want {
+		if !slices.Equal(got[principal], risks) {
+			t.Errorf("%s findings = %v, want %v", principal, got[principal], risks)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package helpers
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// Findings for trust relationships that should be reviewed
+const (
+	IAMTrustFindingWildcard          = "Wildcard principal"
+	IAMTrustFindingMissingExternalID = "Cross-account trust without sts:ExternalId"
+)
+
+var accountIDRegex = regexp.MustCompile(`^\d{12}$`)
+
+// IAMTrustRelationship is a single principal that is allowed to assume a
+// role through the role's trust policy
+type IAMTrustRelationship struct {
+	Role          string
+	PrincipalType string
+	Principal     string
+	AccountID     string
+	CrossAccount  bool
+	Actions       []string
+	Conditional   bool
+	Findings      []string
+}
+
+// PrincipalRoleName returns the name of the role that is the principal, if
+// the principal is a role in the same account. These relationships make up
+// the role chaining paths.
+func (relationship IAMTrustRelationship) PrincipalRoleName() (string, bool) {
+	if relationship.PrincipalType != IAMPrincipalTypeAWS || relationship.CrossAccount {
+		return "", false
+	}
+	parsed, err := arn.Parse(relationship.Principal)
+	if err != nil || !strings.HasPrefix(parsed.Resource, "role/") {
+		return "", false
+	}
+	return parsed.Resource[strings.LastIndex(parsed.Resource, "/")+1:], true
+}
+
+// IsAccountPrincipal returns whether the principal is an entire account,
+// meaning any principal in that account with the right permissions can
+// assume the role
+func (relationship IAMTrustRelationship) IsAccountPrincipal() bool {
+	if relationship.PrincipalType != IAMPrincipalTypeAWS {
+		return false
+	}
+	if accountIDRegex.MatchString(relationship.Principal) {
+		return true
+	}
+	parsed, err := arn.Parse(relationship.Principal)
+	return err == nil && parsed.Resource == "root"
+}
+
+// GetTrustRelationships returns every principal that can assume each of the
+// roles, based on the Allow statements in their trust policies. Principals
+// in other accounts than accountID are marked as cross-account.
+func GetTrustRelationships(roles []IAMRole, accountID string) []IAMTrustRelationship {
+	var result []IAMTrustRelationship
+	for _, role := range roles {
+		for _, statement := range role.AssumeRolePolicy.Statement {
+			if !statementAllowsAssumeRole(statement) {
+				continue
+			}
+			principals := statement.GetPrincipals()
+			principaltypes := make([]string, 0, len(principals))
+			for principaltype := range principals {
+				principaltypes = append(principaltypes, principaltype)
+			}
+			sort.Strings(principaltypes)
+			for _, principaltype := range principaltypes {
+				for _, principal := range principals[principaltype] {
+					result = append(result, newTrustRelationship(role.Name, principaltype, principal, statement, accountID))
+				}
+			}
+		}
+	}
+	return result
+}
+
+func newTrustRelationship(role string, principaltype string, principal string, statement IAMPolicyDocumentStatement, accountID string) IAMTrustRelationship {
+	relationship := IAMTrustRelationship{
+		Role:          role,
+		PrincipalType: principaltype,
+		Principal:     principal,
+		Actions:       normalizeActions(statement.Action),
+		Conditional:   statement.Condition != nil,
+	}
+	if principaltype == IAMPrincipalTypeAWS {
+		relationship.AccountID = principalAccountID(principal)
+		relationship.CrossAccount = relationship.AccountID != "" && relationship.AccountID != accountID
+	}
+	if principal == "*" {
+		relationship.Findings = append(relationship.Findings, IAMTrustFindingWildcard)
+	}
+	if relationship.CrossAccount && !hasConditionKey(statement.Condition, "sts:ExternalId") {
+		relationship.Findings = append(relationship.Findings, IAMTrustFindingMissingExternalID)
+	}
+	return relationship
+}
+
+// principalAccountID returns the account ID for an AWS principal, which can
+// be either an account ID or an ARN
+func principalAccountID(principal string) string {
+	if accountIDRegex.MatchString(principal) {
+		return principal
+	}
+	parsed, err := arn.Parse(principal)
+	if err != nil {
+		return ""
+	}
+	return parsed.AccountID
+}
+
+// hasConditionKey returns whether any of the condition operators in the
+// statement's Condition block uses the condition key
+func hasConditionKey(condition any, key string) bool {
+	operators, ok := condition.(map[string]any)
+	if !ok {
+		return false
+	}
+	for _, values := range operators {
+		keys, ok := values.(map[string]any)
+		if !ok {
+			continue
+		}
+		for conditionkey := range keys {
+			if strings.EqualFold(conditionkey, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// FindRoleChains returns the paths through which roles can be chained, where
+// a role assumes a role that can in turn assume another role. Every path
+// starts with a role that can't be assumed by another role in the account
+// and contains at least two roles. Cycles are cut off at the first repeat.
+func FindRoleChains(relationships []IAMTrustRelationship) [][]string {
+	assumes := make(map[string][]string)
+	assumed := make(map[string]bool)
+	for _, relationship := range relationships {
+		principal, ok := relationship.PrincipalRoleName()
+		if !ok || principal == relationship.Role {
+			continue
+		}
+		assumes[principal] = append(assumes[principal], relationship.Role)
+		assumed[relationship.Role] = true
+	}
+	starts := make([]string, 0, len(assumes))
+	for role := range assumes {
+		if !assumed[role] {
+			starts = append(starts, role)
+		}
+		sort.Strings(assumes[role])
+	}
+	sort.Strings(starts)
+	var result [][]string
+	for _, start := range starts {
+		result = append(result, extendRoleChain([]string{start}, assumes)...)
+	}
+	return result
+}
+
+func extendRoleChain(chain []string, assumes map[string][]string) [][]string {
+	var result [][]string
+	current := chain[len(chain)-1]
+	for _, next := range assumes[current] {
+		if slices.Contains(chain, next) {
+			continue
+		}
+		extended := append(append([]string{}, chain...), next)
+		result = append(result, extendRoleChain(extended, assumes)...)
+	}
+	if len(result) == 0 && len(chain) > 1 {
+		result = append(result, chain)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package helpers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIAMPolicyDocumentStatement_UnmarshalPrincipal(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     map[string][]string
+	}{
+		{
+			name:     "single values",
+			document: `{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"Service":"ec2.amazonaws.com"}}`,
+			want:     map[string][]string{"Service": {"ec2.amazonaws.com"}},
+		},
+		{
+			name:     "list of values",
+			document: `{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":["arn:aws:iam::111111111111:root","arn:aws:iam::222222222222:role/Deploy"]}}`,
+			want:     map[string][]string{"AWS": {"arn:aws:iam::111111111111:root", "arn:aws:iam::222222222222:role/Deploy"}},
+		},
+		{
+			name:     "wildcard",
+			document: `{"Effect":"Allow","Action":"sts:AssumeRole","Principal":"*"}`,
+			want:     map[string][]string{"AWS": {"*"}},
+		},
+		{
+			name:     "no principal",
+			document: `{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}`,
+			want:     map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var statement IAMPolicyDocumentStatement
+			if err := json.Unmarshal([]byte(tt.document), &statement); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got := statement.GetPrincipals(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPrincipals() = %v, want %v", got, tt.want)
+			}
+			if statement.Effect != "Allow" {
+				t.Errorf("Effect = %s, want Allow", statement.Effect)
+			}
+		})
+	}
+}
+
+func trustRole(t *testing.T, name string, document string) IAMRole {
+	t.Helper()
+	role := IAMRole{Name: name, AssumeRolePolicy: IAMPolicyDocument{Type: IAMPolicyTypeAssumeRole}}
+	if err := json.Unmarshal([]byte(document), &role.AssumeRolePolicy); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	return role
+}
+
+func TestGetTrustRelationships(t *testing.T) {
+	roles := []IAMRole{
+		trustRole(t, "Vendor", `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::999999999999:root"}}]}`),
+		trustRole(t, "VendorWithExternalId", `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"999999999999"},"Condition":{"StringEquals":{"sts:ExternalId":"secret"}}}]}`),
+		trustRole(t, "Open", `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":"*"}]}`),
+		trustRole(t, "GitHub", `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRoleWithWebIdentity","Principal":{"Federated":"arn:aws:iam::123456789012:oidc-provider/token.actions.githubusercontent.com"}}]}`),
+		trustRole(t, "Local", `{"Statement":[{"Effect":"Allow","Action":"sts:AssumeRole","Principal":{"AWS":"arn:aws:iam::123456789012:role/Admin"}}]}`),
+	}
+	relationships := GetTrustRelationships(roles, "123456789012")
+	if len(relationships) != 5 {
+		t.Fatalf("got %d relationships, want 5", len(relationships))
+	}
+	tests := []struct {
+		role         string
+		crossAccount bool
+		findings     []string
+	}{
+		{"Vendor", true, []string{IAMTrustFindingMissingExternalID}},
+		{"VendorWithExternalId", true, nil},
+		{"Open", false, []string{IAMTrustFindingWildcard}},
+		{"GitHub", false, nil},
+		{"Local", false, nil},
+	}
+	for i, tt := range tests {
+		relationship := relationships[i]
+		if relationship.Role != tt.role {
+			t.Fatalf("relationship %d is for %s, want %s", i, relationship.Role, tt.role)
+		}
+		if relationship.CrossAccount != tt.crossAccount {
+			t.Errorf("%s CrossAccount = %v, want %v", tt.role, relationship.CrossAccount, tt.crossAccount)
+		}
+		if !reflect.DeepEqual(relationship.Findings, tt.findings) {
+			t.Errorf("%s Findings = %v, want %v", tt.role, relationship.Findings, tt.findings)
+		}
+	}
+	if !relationships[0].IsAccountPrincipal() || !relationships[1].IsAccountPrincipal() || relationships[4].IsAccountPrincipal() {
+		t.Errorf("IsAccountPrincipal() not detected correctly")
+	}
+	if name, ok := relationships[4].PrincipalRoleName(); !ok || name != "Admin" {
+		t.Errorf("PrincipalRoleName() = %s, %v, want Admin, true", name, ok)
+	}
+	if relationships[3].PrincipalType != IAMPrincipalTypeFederated {
+		t.Errorf("PrincipalType = %s, want %s", relationships[3].PrincipalType, IAMPrincipalTypeFederated)
+	}
+}
+
+func TestFindRoleChains(t *testing.T) {
+	role := func(name string) string { return "arn:aws:iam::123456789012:role/" + name }
+	relationships := []IAMTrustRelationship{
+		{Role: "Deploy", PrincipalType: IAMPrincipalTypeAWS, Principal: role("CI")},
+		{Role: "Prod", PrincipalType: IAMPrincipalTypeAWS, Principal: role("Deploy")},
+		{Role: "Staging", PrincipalType: IAMPrincipalTypeAWS, Principal: role("Deploy")},
+		{Role: "CI", PrincipalType: IAMPrincipalTypeService, Principal: "codebuild.amazonaws.com"},
+		// Cycle between A and B, reachable from Start
+		{Role: "A", PrincipalType: IAMPrincipalTypeAWS, Principal: role("Start")},
+		{Role: "B", PrincipalType: IAMPrincipalTypeAWS, Principal: role("A")},
+		{Role: "A", PrincipalType: IAMPrincipalTypeAWS, Principal: role("B")},
+		// Cross-account roles aren't part of chains
+		{Role: "Other", PrincipalType: IAMPrincipalTypeAWS, Principal: "arn:aws:iam::999999999999:role/Remote", CrossAccount: true},
+	}
+	want := [][]string{
+		{"CI", "Deploy", "Prod"},
+		{"CI", "Deploy", "Staging"},
+		{"Start", "A", "B"},
+	}
+	if got := FindRoleChains(relationships); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindRoleChains() = %v, want %v", got, want)
+	}
+}
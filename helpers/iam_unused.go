@@ -0,0 +1,168 @@
+package helpers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+// IAMUnusedPrincipal is a role, user, or group that hasn't been used within
+// the inactivity threshold. LastUsedRegion is only set for roles, and UsedVia
+// (Console or Access key) only for users.
+type IAMUnusedPrincipal struct {
+	Name           string
+	Type           string
+	Created        time.Time
+	LastUsed       time.Time
+	LastUsedRegion string
+	UsedVia        string
+	ServiceLinked  bool
+}
+
+// NeverUsed returns whether there is no record of the principal being used
+func (principal IAMUnusedPrincipal) NeverUsed() bool {
+	return principal.LastUsed.IsZero()
+}
+
+// DaysInactive returns the number of days since the principal was last used,
+// or since it was created if it was never used
+func (principal IAMUnusedPrincipal) DaysInactive(now time.Time) int {
+	since := principal.LastUsed
+	if since.IsZero() {
+		since = principal.Created
+	}
+	return int(now.Sub(since).Hours() / 24)
+}
+
+// IAMUnusedReport contains the principals that haven't been used within the
+// inactivity threshold. Service-linked roles are reported separately, as
+// they can only be deleted through the service that created them.
+type IAMUnusedReport struct {
+	Roles              []IAMUnusedPrincipal
+	ServiceLinkedRoles []IAMUnusedPrincipal
+	Users              []IAMUnusedPrincipal
+	EmptyGroups        []IAMUnusedPrincipal
+}
+
+// GetUnusedIAMPrincipals returns the roles and users that haven't been used
+// in the provided number of days, and the groups without members. Principals
+// that were created within the threshold aren't reported.
+func GetUnusedIAMPrincipals(days int, svc IAMClient) IAMUnusedReport {
+	return getUnusedIAMPrincipals(time.Now().AddDate(0, 0, -days), svc)
+}
+
+func getUnusedIAMPrincipals(cutoff time.Time, svc IAMClient) IAMUnusedReport {
+	report := IAMUnusedReport{}
+	for _, role := range getUnusedRoles(cutoff, svc) {
+		if role.ServiceLinked {
+			report.ServiceLinkedRoles = append(report.ServiceLinkedRoles, role)
+		} else {
+			report.Roles = append(report.Roles, role)
+		}
+	}
+	report.Users = getUnusedUsers(cutoff, svc)
+	report.EmptyGroups = getEmptyGroups(svc)
+	return report
+}
+
+// isInactive returns whether the principal was last used, or created if it
+// was never used, before the cutoff
+func isInactive(created time.Time, lastUsed time.Time, cutoff time.Time) bool {
+	if lastUsed.IsZero() {
+		return created.Before(cutoff)
+	}
+	return lastUsed.Before(cutoff)
+}
+
+// isServiceLinkedRole returns whether the role is a service-linked role.
+// Unlike other service roles, these live under the /aws-service-role/ path.
+func isServiceLinkedRole(role types.Role) bool {
+	return strings.HasPrefix(aws.ToString(role.Path), "/aws-service-role/")
+}
+
+// getUnusedRoles returns the roles that weren't used since the cutoff. As
+// ListRoles doesn't return the RoleLastUsed information, GetRole is called
+// for every role.
+func getUnusedRoles(cutoff time.Time, svc IAMClient) []IAMUnusedPrincipal {
+	var result []IAMUnusedPrincipal
+	paginator := iam.NewListRolesPaginator(svc, &iam.ListRolesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, role := range page.Roles {
+			resp, err := svc.GetRole(context.TODO(), &iam.GetRoleInput{RoleName: role.RoleName})
+			if err != nil {
+				panic(err)
+			}
+			principal := IAMUnusedPrincipal{
+				Name:          aws.ToString(role.RoleName),
+				Type:          IAMObjectTypeRole,
+				Created:       aws.ToTime(role.CreateDate),
+				ServiceLinked: isServiceLinkedRole(role),
+			}
+			if resp.Role != nil && resp.Role.RoleLastUsed != nil && resp.Role.RoleLastUsed.LastUsedDate != nil {
+				principal.LastUsed = *resp.Role.RoleLastUsed.LastUsedDate
+				principal.LastUsedRegion = aws.ToString(resp.Role.RoleLastUsed.Region)
+			}
+			if isInactive(principal.Created, principal.LastUsed, cutoff) {
+				result = append(result, principal)
+			}
+		}
+	}
+	return result
+}
+
+// getUnusedUsers returns the users whose password and access keys weren't
+// used since the cutoff
+func getUnusedUsers(cutoff time.Time, svc IAMClient) []IAMUnusedPrincipal {
+	var result []IAMUnusedPrincipal
+	for _, user := range getUserList(svc) {
+		iamuser := IAMUser{Name: aws.ToString(user.UserName), User: &user}
+		principal := IAMUnusedPrincipal{
+			Name:    iamuser.Name,
+			Type:    IAMObjectTypeUser,
+			Created: aws.ToTime(user.CreateDate),
+		}
+		if iamuser.HasUsedPassword() {
+			principal.LastUsed = iamuser.GetLastPasswordDate()
+			principal.UsedVia = "Console"
+		}
+		// Users without access keys get a zero date, so no separate check is needed
+		if keydate := iamuser.GetLastAccessKeyDate(svc); keydate.After(principal.LastUsed) {
+			principal.LastUsed = keydate
+			principal.UsedVia = "Access key"
+		}
+		if isInactive(principal.Created, principal.LastUsed, cutoff) {
+			result = append(result, principal)
+		}
+	}
+	return result
+}
+
+// getEmptyGroups returns the groups that don't have any members
+func getEmptyGroups(svc IAMClient) []IAMUnusedPrincipal {
+	var result []IAMUnusedPrincipal
+	paginator := iam.NewListGroupsPaginator(svc, &iam.ListGroupsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, group := range page.Groups {
+			if len(getAllUsersInGroup(aws.ToString(group.GroupName), svc)) == 0 {
+				result = append(result, IAMUnusedPrincipal{
+					Name:    aws.ToString(group.GroupName),
+					Type:    IAMObjectTypeGroup,
+					Created: aws.ToTime(group.CreateDate),
+				})
+			}
+		}
+	}
+	return result
+}
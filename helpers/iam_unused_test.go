@@ -0,0 +1,96 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+)
+
+func TestIsInactive(t *testing.T) {
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := cutoff.AddDate(0, -3, 0)
+	recent := cutoff.AddDate(0, 0, 10)
+	tests := []struct {
+		name     string
+		created  time.Time
+		lastUsed time.Time
+		want     bool
+	}{
+		{"never used, created before cutoff", old, time.Time{}, true},
+		{"never used, created after cutoff", recent, time.Time{}, false},
+		{"used before cutoff", old, old.AddDate(0, 1, 0), true},
+		{"used after cutoff", old, recent, false},
+	}
+	for _, tt := range tests {
+		if got := isInactive(tt.created, tt.lastUsed, cutoff); got != tt.want {
+			t.Errorf("%s: isInactive() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIAMUnusedPrincipal_DaysInactive(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	principal := IAMUnusedPrincipal{Created: now.AddDate(0, 0, -100)}
+	if got := principal.DaysInactive(now); got != 100 {
+		t.Errorf("DaysInactive() for never used = %d, want 100", got)
+	}
+	principal.LastUsed = now.AddDate(0, 0, -30)
+	if got := principal.DaysInactive(now); got != 30 {
+		t.Errorf("DaysInactive() = %d, want 30", got)
+	}
+}
+
+func TestGetUnusedIAMPrincipals(t *testing.T) {
+	cachedUsers = nil
+	defer func() { cachedUsers = nil }()
+	cutoff := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	old := aws.Time(cutoff.AddDate(-1, 0, 0))
+	recent := aws.Time(cutoff.AddDate(0, 0, 5))
+	mock := &mockIAMClient{
+		roles: []types.Role{
+			{RoleName: aws.String("stale"), Path: aws.String("/"), CreateDate: old, RoleLastUsed: &types.RoleLastUsed{LastUsedDate: old, Region: aws.String("us-east-1")}},
+			{RoleName: aws.String("active"), Path: aws.String("/"), CreateDate: old, RoleLastUsed: &types.RoleLastUsed{LastUsedDate: recent}},
+			{RoleName: aws.String("new"), Path: aws.String("/"), CreateDate: recent},
+			{RoleName: aws.String("AWSServiceRoleForSupport"), Path: aws.String("/aws-service-role/support.amazonaws.com/"), CreateDate: old},
+			{RoleName: aws.String("service-role"), Path: aws.String("/service-role/"), CreateDate: old},
+		},
+		users: []types.User{
+			{UserName: aws.String("idle"), CreateDate: old, PasswordLastUsed: old},
+			{UserName: aws.String("console"), CreateDate: old, PasswordLastUsed: recent},
+			{UserName: aws.String("never"), CreateDate: old},
+		},
+		groups: []types.Group{
+			{GroupName: aws.String("empty"), CreateDate: old},
+			{GroupName: aws.String("admins"), CreateDate: old},
+		},
+		usersInGroup: map[string][]types.User{
+			"admins": {{UserName: aws.String("console")}},
+		},
+	}
+	report := getUnusedIAMPrincipals(cutoff, mock)
+
+	assertNames := func(label string, principals []IAMUnusedPrincipal, want []string) {
+		t.Helper()
+		if len(principals) != len(want) {
+			t.Fatalf("%s: got %d principals, want %v", label, len(principals), want)
+		}
+		for i, principal := range principals {
+			if principal.Name != want[i] {
+				t.Errorf("%s[%d] = %s, want %s", label, i, principal.Name, want[i])
+			}
+		}
+	}
+	assertNames("Roles", report.Roles, []string{"stale", "service-role"})
+	assertNames("ServiceLinkedRoles", report.ServiceLinkedRoles, []string{"AWSServiceRoleForSupport"})
+	assertNames("Users", report.Users, []string{"idle", "never"})
+	assertNames("EmptyGroups", report.EmptyGroups, []string{"empty"})
+
+	if report.Roles[0].LastUsedRegion != "us-east-1" || report.Roles[0].UsedVia != "" {
+		t.Errorf("LastUsedRegion = %s, UsedVia = %s, want only the region us-east-1", report.Roles[0].LastUsedRegion, report.Roles[0].UsedVia)
+	}
+	if report.Users[0].UsedVia != "Console" || report.Users[0].LastUsedRegion != "" || !report.Users[1].NeverUsed() {
+		t.Errorf("unexpected user last use details: %+v", report.Users)
+	}
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"maps"
 	"sort"
@@ -22,21 +23,29 @@ const (
 
 // IAM Policy Type
 const (
-	IAMPolicyTypeAttached   = "Attached Policy"
-	IAMPolicyTypeInline     = "Inline Policy"
-	IAMPolicyTypeAssumeRole = "Assume Role Policy"
+	IAMPolicyTypeAttached            = "Attached Policy"
+	IAMPolicyTypeInline              = "Inline Policy"
+	IAMPolicyTypeAssumeRole          = "Assume Role Policy"
+	IAMPolicyTypeGroupAttached       = "Group Attached Policy"
+	IAMPolicyTypeGroupInline         = "Group Inline Policy"
+	IAMPolicyTypePermissionsBoundary = "Permissions Boundary"
+	IAMPolicyTypeSCP                 = "Service Control Policy"
 )
 
 // IAM Principal Type
 const (
-	IAMPrincipalTypeService = "Service"
-	IAMPrincipalTypeAWS     = "AWS"
+	IAMPrincipalTypeService       = "Service"
+	IAMPrincipalTypeAWS           = "AWS"
+	IAMPrincipalTypeFederated     = "Federated"
+	IAMPrincipalTypeCanonicalUser = "CanonicalUser"
 )
 
 // IAM Object Type
 const (
-	IAMObjectTypeGroup = "Group"
-	IAMObjectTypeUser  = "User"
+	IAMObjectTypeGroup         = "Group"
+	IAMObjectTypeUser          = "User"
+	IAMObjectTypeRole          = "Role"
+	IAMObjectTypePermissionSet = "Permission Set"
 )
 
 // IAMObject interface for IAM objects
@@ -68,6 +77,7 @@ type IAMRole struct {
 var assumeRoleActions = []string{
 	"sts:AssumeRole",
 	"sts:AssumeRoleWithSAML",
+	"sts:AssumeRoleWithWebIdentity",
 }
 
 // statementAllowsAssumeRole reports whether a trust-policy statement allows
@@ -173,11 +183,100 @@ func (policy *IAMPolicyDocument) GetRoleNames() []string {
 
 // IAMPolicyDocumentStatement is an abstracted version of a Statement for a policy document
 type IAMPolicyDocumentStatement struct {
-	Effect    string
-	Principal map[string]string
-	Action    any
-	Condition any
-	Resource  any
+	Sid         string
+	Effect      string
+	Principal   map[string]string
+	Action      any
+	NotAction   any
+	Condition   any
+	Resource    any
+	NotResource any
+}
+
+// iamPrincipalSeparator joins multiple principals of the same type in the
+// Principal map, as neither ARNs nor service names contain it
+const iamPrincipalSeparator = ", "
+
+// UnmarshalJSON parses a statement, accepting every format AWS allows for the
+// Principal: the "*" wildcard, a single value per type, or a list of values
+// per type. Multiple values of the same type are joined with a comma.
+func (statement *IAMPolicyDocumentStatement) UnmarshalJSON(data []byte) error {
+	type plainStatement IAMPolicyDocumentStatement
+	var raw struct {
+		plainStatement
+		Principal any
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*statement = IAMPolicyDocumentStatement(raw.plainStatement)
+	switch principal := raw.Principal.(type) {
+	case nil:
+		statement.Principal = nil
+	case string:
+		statement.Principal = map[string]string{IAMPrincipalTypeAWS: principal}
+	case map[string]any:
+		statement.Principal = make(map[string]string, len(principal))
+		for key, value := range principal {
+			statement.Principal[key] = strings.Join(normalizeActions(value), iamPrincipalSeparator)
+		}
+	default:
+		return fmt.Errorf("unsupported Principal format in policy statement: %v", principal)
+	}
+	return nil
+}
+
+// GetPrincipals returns all principals of the statement by principal type
+func (statement IAMPolicyDocumentStatement) GetPrincipals() map[string][]string {
+	result := make(map[string][]string, len(statement.Principal))
+	for key, value := range statement.Principal {
+		result[key] = strings.Split(value, iamPrincipalSeparator)
+	}
+	return result
+}
+
+// iamNotPrefix marks actions and resources that come from a NotAction or
+// NotResource element
+const iamNotPrefix = "NOT "
+
+// GetActions returns the actions of the statement. Actions from a NotAction
+// element are prefixed with "NOT ".
+func (statement IAMPolicyDocumentStatement) GetActions() []string {
+	if statement.Action == nil {
+		return prefixAll(iamNotPrefix, normalizeActions(statement.NotAction))
+	}
+	return normalizeActions(statement.Action)
+}
+
+// GetResources returns the resources of the statement. Resources from a
+// NotResource element are prefixed with "NOT ".
+func (statement IAMPolicyDocumentStatement) GetResources() []string {
+	if statement.Resource == nil {
+		return prefixAll(iamNotPrefix, normalizeActions(statement.NotResource))
+	}
+	return normalizeActions(statement.Resource)
+}
+
+// GetCondition returns the condition block of the statement as JSON, or an
+// empty string if it has no conditions
+func (statement IAMPolicyDocumentStatement) GetCondition() string {
+	if statement.Condition == nil {
+		return ""
+	}
+	// json.Marshal sorts map keys, so equal conditions give equal strings
+	encoded, err := json.Marshal(statement.Condition)
+	if err != nil {
+		return fmt.Sprintf("%v", statement.Condition)
+	}
+	return string(encoded)
+}
+
+func prefixAll(prefix string, values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		result = append(result, prefix+value)
+	}
+	return result
 }
 
 // IAMUser contains information about IAM Users
@@ -3,6 +3,9 @@ package helpers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
@@ -32,6 +35,11 @@ func getOrganizationRoot(svc OrganizationsAPI) (OrganizationEntry, error) {
 		Name: aws.ToString(rootentry.Name),
 		Type: string(types.TargetTypeRoot),
 	}
+	for _, policytype := range rootentry.PolicyTypes {
+		if policytype.Status == types.PolicyTypeStatusEnabled {
+			entry.PolicyTypes = append(entry.PolicyTypes, policytype.Type)
+		}
+	}
 	return entry, nil
 }
 
@@ -56,6 +64,17 @@ type OrganizationEntry struct {
 	Arn      string
 	Type     string
 	Children []OrganizationEntry
+	// Path contains the names of the root and OUs above the entry, separated by slashes
+	Path string
+	// Email, Status, JoinedMethod, and JoinedTimestamp are only set for accounts
+	Email           string
+	Status          string
+	JoinedMethod    string
+	JoinedTimestamp time.Time
+	// Tags are only set after calling AddTags
+	Tags map[string]string
+	// PolicyTypes are the policy types enabled in the organization and only set for the root
+	PolicyTypes []types.PolicyType
 }
 
 func (entry *OrganizationEntry) findChildren(svc OrganizationsAPI) ([]OrganizationEntry, error) {
@@ -74,6 +93,7 @@ func (entry *OrganizationEntry) findChildren(svc OrganizationsAPI) ([]Organizati
 			if err != nil {
 				return nil, err
 			}
+			ouchild.Path = entry.childPath()
 			ouchildChildren, err := ouchild.findChildren(svc)
 			if err != nil {
 				return nil, err
@@ -100,6 +120,7 @@ func (entry *OrganizationEntry) findChildren(svc OrganizationsAPI) ([]Organizati
 			if err != nil {
 				return nil, err
 			}
+			accountchild.Path = entry.childPath()
 			children = append(children, accountchild)
 		}
 		if accountchildren.NextToken == nil {
@@ -114,6 +135,78 @@ func (entry *OrganizationEntry) String() string {
 	return entry.Name + " (" + entry.ID + ")"
 }
 
+// DisplayName returns the name and ID of the entry, followed by the status
+// for accounts that aren't active
+func (entry *OrganizationEntry) DisplayName() string {
+	if entry.Status == "" || entry.Status == string(types.AccountStatusActive) {
+		return entry.String()
+	}
+	return entry.String() + " [" + entry.Status + "]"
+}
+
+// IsSuspended returns whether the entry is a suspended account
+func (entry *OrganizationEntry) IsSuspended() bool {
+	return entry.Status == string(types.AccountStatusSuspended)
+}
+
+// childPath returns the Path of the entry's children
+func (entry *OrganizationEntry) childPath() string {
+	if entry.Path == "" {
+		return entry.Name
+	}
+	return entry.Path + "/" + entry.Name
+}
+
+// AddTags retrieves the tags of the entry and all of its children
+func (entry *OrganizationEntry) AddTags(svc organizations.ListTagsForResourceAPIClient) error {
+	entry.Tags = make(map[string]string)
+	paginator := organizations.NewListTagsForResourcePaginator(svc, &organizations.ListTagsForResourceInput{ResourceId: aws.String(entry.ID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return fmt.Errorf("failed to list tags of %s: %w", entry.ID, err)
+		}
+		for _, tag := range page.Tags {
+			entry.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	for i := range entry.Children {
+		if err := entry.Children[i].AddTags(svc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetTagList returns the tags of the entry as sorted Key=Value strings
+func (entry *OrganizationEntry) GetTagList() []string {
+	result := make([]string, 0, len(entry.Tags))
+	for key, value := range entry.Tags {
+		result = append(result, key+"="+value)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Tree returns the entry and its children as an indented text tree
+func (entry *OrganizationEntry) Tree() string {
+	var builder strings.Builder
+	builder.WriteString(entry.DisplayName() + "\n")
+	entry.writeTree(&builder, "")
+	return builder.String()
+}
+
+func (entry *OrganizationEntry) writeTree(builder *strings.Builder, indent string) {
+	for i, child := range entry.Children {
+		branch, childIndent := "├── ", "│   "
+		if i == len(entry.Children)-1 {
+			branch, childIndent = "└── ", "    "
+		}
+		builder.WriteString(indent + branch + child.DisplayName() + "\n")
+		child.writeTree(builder, indent+childIndent)
+	}
+}
+
 func formatChild(raw types.Child, svc OrganizationsAPI) (OrganizationEntry, error) {
 	if raw.Type == types.ChildType(types.TargetTypeOrganizationalUnit) {
 		input := &organizations.DescribeOrganizationalUnitInput{
@@ -139,10 +232,14 @@ func formatChild(raw types.Child, svc OrganizationsAPI) (OrganizationEntry, erro
 		return OrganizationEntry{}, fmt.Errorf("failed to describe account %s: %w", *raw.Id, err)
 	}
 	return OrganizationEntry{
-		Name:     *details.Account.Name,
-		ID:       *details.Account.Id,
-		Type:     string(raw.Type),
-		Arn:      *details.Account.Arn,
-		Children: []OrganizationEntry{},
+		Name:            *details.Account.Name,
+		ID:              *details.Account.Id,
+		Type:            string(raw.Type),
+		Arn:             *details.Account.Arn,
+		Children:        []OrganizationEntry{},
+		Email:           aws.ToString(details.Account.Email),
+		Status:          string(details.Account.Status),
+		JoinedMethod:    string(details.Account.JoinedMethod),
+		JoinedTimestamp: aws.ToTime(details.Account.JoinedTimestamp),
 	}, nil
 }
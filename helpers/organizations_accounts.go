@@ -0,0 +1,95 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// OrganizationsDelegationAPI defines the Organizations calls used to find the
+// delegated administrator accounts and their services
+type OrganizationsDelegationAPI interface {
+	organizations.ListDelegatedAdministratorsAPIClient
+	organizations.ListDelegatedServicesForAccountAPIClient
+}
+
+// OrganizationAccount is an account in the organization with the services it
+// is a delegated administrator for and the policies attached directly to it
+type OrganizationAccount struct {
+	OrganizationEntry
+	DelegatedServices []string
+	Policies          []OrganizationPolicy
+}
+
+// GetPolicyNames returns the names of the account's policies, prefixed with
+// their type
+func (account OrganizationAccount) GetPolicyNames() []string {
+	result := make([]string, 0, len(account.Policies))
+	for _, policy := range account.Policies {
+		result = append(result, fmt.Sprintf("%s: %s", policy.Type, policy.Name))
+	}
+	return result
+}
+
+// GetAccounts returns all accounts below the entry, in the order they appear
+// in the organization
+func (entry *OrganizationEntry) GetAccounts() []OrganizationEntry {
+	var result []OrganizationEntry
+	for _, child := range entry.Children {
+		if child.Type == string(types.TargetTypeAccount) {
+			result = append(result, child)
+		}
+		result = append(result, child.GetAccounts()...)
+	}
+	return result
+}
+
+// GetDelegatedAdministrators returns the service principals every delegated
+// administrator account is registered for, keyed by account ID
+func GetDelegatedAdministrators(svc OrganizationsDelegationAPI) (map[string][]string, error) {
+	result := make(map[string][]string)
+	paginator := organizations.NewListDelegatedAdministratorsPaginator(svc, &organizations.ListDelegatedAdministratorsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list delegated administrators: %w", err)
+		}
+		for _, admin := range page.DelegatedAdministrators {
+			accountID := aws.ToString(admin.Id)
+			services := organizations.NewListDelegatedServicesForAccountPaginator(svc, &organizations.ListDelegatedServicesForAccountInput{AccountId: admin.Id})
+			for services.HasMorePages() {
+				servicepage, err := services.NextPage(context.TODO())
+				if err != nil {
+					return nil, fmt.Errorf("failed to list delegated services for account %s: %w", accountID, err)
+				}
+				for _, service := range servicepage.DelegatedServices {
+					result[accountID] = append(result[accountID], aws.ToString(service.ServicePrincipal))
+				}
+			}
+			sort.Strings(result[accountID])
+		}
+	}
+	return result, nil
+}
+
+// GetOrganizationAccounts combines the accounts of the organization with
+// their delegated administrator services and the policies directly attached
+// to them
+func GetOrganizationAccounts(organization OrganizationEntry, delegated map[string][]string, policies []OrganizationPolicy) []OrganizationAccount {
+	accounts := organization.GetAccounts()
+	result := make([]OrganizationAccount, 0, len(accounts))
+	for _, entry := range accounts {
+		account := OrganizationAccount{OrganizationEntry: entry, DelegatedServices: delegated[entry.ID]}
+		for _, policy := range policies {
+			if policy.IsAttachedTo(entry.ID) {
+				account.Policies = append(account.Policies, policy)
+			}
+		}
+		result = append(result, account)
+	}
+	return result
+}
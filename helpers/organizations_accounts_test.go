@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOrganizationsDelegationClient implements OrganizationsDelegationAPI for testing.
+type mockOrganizationsDelegationClient struct {
+	services map[string][]string
+}
+
+func (m *mockOrganizationsDelegationClient) ListDelegatedAdministrators(_ context.Context, _ *organizations.ListDelegatedAdministratorsInput, _ ...func(*organizations.Options)) (*organizations.ListDelegatedAdministratorsOutput, error) {
+	output := &organizations.ListDelegatedAdministratorsOutput{}
+	for id := range m.services {
+		output.DelegatedAdministrators = append(output.DelegatedAdministrators, orgtypes.DelegatedAdministrator{Id: aws.String(id)})
+	}
+	return output, nil
+}
+
+func (m *mockOrganizationsDelegationClient) ListDelegatedServicesForAccount(_ context.Context, params *organizations.ListDelegatedServicesForAccountInput, _ ...func(*organizations.Options)) (*organizations.ListDelegatedServicesForAccountOutput, error) {
+	output := &organizations.ListDelegatedServicesForAccountOutput{}
+	for _, service := range m.services[aws.ToString(params.AccountId)] {
+		output.DelegatedServices = append(output.DelegatedServices, orgtypes.DelegatedService{ServicePrincipal: aws.String(service)})
+	}
+	return output, nil
+}
+
+func TestOrganizationEntry_GetAccounts(t *testing.T) {
+	organization := testOrganization()
+	accounts := organization.GetAccounts()
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "111111111111", accounts[0].ID)
+	assert.Equal(t, "222222222222", accounts[1].ID)
+}
+
+func TestGetDelegatedAdministrators(t *testing.T) {
+	mock := &mockOrganizationsDelegationClient{services: map[string][]string{
+		"111111111111": {"securityhub.amazonaws.com", "guardduty.amazonaws.com"},
+	}}
+	result, err := GetDelegatedAdministrators(mock)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"111111111111": {"guardduty.amazonaws.com", "securityhub.amazonaws.com"}}, result)
+}
+
+func TestGetOrganizationAccounts(t *testing.T) {
+	delegated := map[string][]string{"111111111111": {"guardduty.amazonaws.com"}}
+	policies := testSCPs()
+	for i := range policies {
+		policies[i].Type = string(orgtypes.PolicyTypeServiceControlPolicy)
+	}
+	policies = append(policies, OrganizationPolicy{ID: "p-tags", Name: "CostCenter", Type: string(orgtypes.PolicyTypeTagPolicy),
+		Targets: []OrganizationPolicyTarget{{ID: "222222222222"}}})
+
+	accounts := GetOrganizationAccounts(testOrganization(), delegated, policies)
+	require.Len(t, accounts, 2)
+	assert.Equal(t, []string{"guardduty.amazonaws.com"}, accounts[0].DelegatedServices)
+	// Only directly attached policies are included, not those of the OUs above
+	assert.Equal(t, []string{"SERVICE_CONTROL_POLICY: FullAWSAccess"}, accounts[0].GetPolicyNames())
+	assert.Empty(t, accounts[1].DelegatedServices)
+	assert.Equal(t, []string{"TAG_POLICY: CostCenter"}, accounts[1].GetPolicyNames())
+}
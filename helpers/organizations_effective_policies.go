@@ -0,0 +1,209 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// OrganizationsEffectivePolicyAPI defines the Organizations call used to
+// retrieve the effective management policy of an account
+type OrganizationsEffectivePolicyAPI interface {
+	DescribeEffectivePolicy(ctx context.Context, params *organizations.DescribeEffectivePolicyInput, optFns ...func(*organizations.Options)) (*organizations.DescribeEffectivePolicyOutput, error)
+}
+
+// TagPolicyRule is a tag key defined in a tag policy, with the values it
+// allows and the resource types for which noncompliant operations are blocked
+type TagPolicyRule struct {
+	Key           string
+	AllowedValues []string
+	EnforcedFor   []string
+}
+
+// IsEnforced returns whether noncompliant operations are blocked for any
+// resource type
+func (rule TagPolicyRule) IsEnforced() bool {
+	return len(rule.EnforcedFor) > 0
+}
+
+// BackupPlan is a backup plan defined in a backup policy
+type BackupPlan struct {
+	Name       string
+	Regions    []string
+	Rules      []BackupPlanRule
+	Selections []string
+}
+
+// BackupPlanRule is a rule of a backup plan
+type BackupPlanRule struct {
+	Name        string
+	Schedule    string
+	TargetVault string
+}
+
+// AIServiceOptOut is the opt-out setting for an AI service, where the
+// service default applies to all services that aren't set separately
+type AIServiceOptOut struct {
+	Service string
+	Setting string
+}
+
+// GetEffectivePolicy returns the content of the effective policy of the
+// provided type for the account, or an empty string if none applies. SCPs
+// have no effective policy.
+func GetEffectivePolicy(policytype types.EffectivePolicyType, accountID string, svc OrganizationsEffectivePolicyAPI) (string, error) {
+	resp, err := svc.DescribeEffectivePolicy(context.TODO(), &organizations.DescribeEffectivePolicyInput{
+		PolicyType: policytype,
+		TargetId:   aws.String(accountID),
+	})
+	if err != nil {
+		var notFound *types.EffectivePolicyNotFoundException
+		if errors.As(err, &notFound) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to describe effective %s for account %s: %w", policytype, accountID, err)
+	}
+	if resp.EffectivePolicy == nil {
+		return "", nil
+	}
+	return aws.ToString(resp.EffectivePolicy.PolicyContent), nil
+}
+
+// ParseTagPolicy returns the tag rules of a tag policy, sorted by key. It
+// accepts both effective policies and the policies attached to a target, in
+// which case the inheritance operators are ignored.
+func ParseTagPolicy(content string) ([]TagPolicyRule, error) {
+	var policy struct {
+		Tags map[string]map[string]any `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(content), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse tag policy: %w", err)
+	}
+	result := make([]TagPolicyRule, 0, len(policy.Tags))
+	for name, tag := range policy.Tags {
+		rule := TagPolicyRule{
+			Key:           policyString(tag["tag_key"]),
+			AllowedValues: policyStrings(tag["tag_value"]),
+			EnforcedFor:   policyStrings(tag["enforced_for"]),
+		}
+		if rule.Key == "" {
+			rule.Key = name
+		}
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Key) < strings.ToLower(result[j].Key)
+	})
+	return result, nil
+}
+
+// ParseBackupPolicy returns the backup plans of a backup policy, sorted by
+// name
+func ParseBackupPolicy(content string) ([]BackupPlan, error) {
+	var policy struct {
+		Plans map[string]map[string]any `json:"plans"`
+	}
+	if err := json.Unmarshal([]byte(content), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse backup policy: %w", err)
+	}
+	result := make([]BackupPlan, 0, len(policy.Plans))
+	for name, plan := range policy.Plans {
+		backupplan := BackupPlan{Name: name, Regions: policyStrings(plan["regions"])}
+		for rulename, rawrule := range policyElements(plan["rules"]) {
+			rule := policyElements(rawrule)
+			backupplan.Rules = append(backupplan.Rules, BackupPlanRule{
+				Name:        rulename,
+				Schedule:    policyString(rule["schedule_expression"]),
+				TargetVault: policyString(rule["target_backup_vault_name"]),
+			})
+		}
+		sort.Slice(backupplan.Rules, func(i, j int) bool {
+			return backupplan.Rules[i].Name < backupplan.Rules[j].Name
+		})
+		for _, rawselection := range policyElements(policyElements(plan["selections"])["tags"]) {
+			selection := policyElements(rawselection)
+			backupplan.Selections = append(backupplan.Selections, fmt.Sprintf("%s=%s", policyString(selection["tag_key"]), strings.Join(policyStrings(selection["tag_value"]), "|")))
+		}
+		sort.Strings(backupplan.Selections)
+		result = append(result, backupplan)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// ParseAIServicesOptOutPolicy returns the opt-out setting of every service in
+// an AI services opt-out policy, with the default for all services first
+func ParseAIServicesOptOutPolicy(content string) ([]AIServiceOptOut, error) {
+	var policy struct {
+		Services map[string]map[string]any `json:"services"`
+	}
+	if err := json.Unmarshal([]byte(content), &policy); err != nil {
+		return nil, fmt.Errorf("failed to parse AI services opt-out policy: %w", err)
+	}
+	result := make([]AIServiceOptOut, 0, len(policy.Services))
+	for service, setting := range policy.Services {
+		result = append(result, AIServiceOptOut{Service: service, Setting: policyString(setting["opt_out_policy"])})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Service == "default" || result[j].Service == "default" {
+			return result[i].Service == "default"
+		}
+		return result[i].Service < result[j].Service
+	})
+	return result, nil
+}
+
+// policyValue returns the value of a management policy element, unwrapping
+// the @@assign, @@append, and @@remove inheritance operators used in
+// attached policies. Effective policies don't contain these operators.
+func policyValue(value any) any {
+	element, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+	for _, operator := range []string{"@@assign", "@@append", "@@remove"} {
+		if inner, ok := element[operator]; ok {
+			return inner
+		}
+	}
+	return value
+}
+
+func policyString(value any) string {
+	if text, ok := policyValue(value).(string); ok {
+		return text
+	}
+	return ""
+}
+
+func policyStrings(value any) []string {
+	switch typed := policyValue(value).(type) {
+	case string:
+		return []string{typed}
+	case []any:
+		result := make([]string, 0, len(typed))
+		for _, item := range typed {
+			if text, ok := item.(string); ok {
+				result = append(result, text)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func policyElements(value any) map[string]any {
+	if element, ok := value.(map[string]any); ok {
+		return element
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOrganizationsEffectivePolicyClient implements OrganizationsEffectivePolicyAPI for testing.
+type mockOrganizationsEffectivePolicyClient struct {
+	contents map[string]string
+}
+
+func (m *mockOrganizationsEffectivePolicyClient) DescribeEffectivePolicy(_ context.Context, params *organizations.DescribeEffectivePolicyInput, _ ...func(*organizations.Options)) (*organizations.DescribeEffectivePolicyOutput, error) {
+	content, ok := m.contents[aws.ToString(params.TargetId)]
+	if !ok {
+		return nil, &orgtypes.EffectivePolicyNotFoundException{Message: aws.String("no effective policy")}
+	}
+	return &organizations.DescribeEffectivePolicyOutput{EffectivePolicy: &orgtypes.EffectivePolicy{PolicyContent: aws.String(content)}}, nil
+}
+
+func TestGetEffectivePolicy(t *testing.T) {
+	mock := &mockOrganizationsEffectivePolicyClient{contents: map[string]string{"111111111111": `{"tags":{}}`}}
+	content, err := GetEffectivePolicy(orgtypes.EffectivePolicyTypeTagPolicy, "111111111111", mock)
+	require.NoError(t, err)
+	assert.Equal(t, `{"tags":{}}`, content)
+
+	content, err = GetEffectivePolicy(orgtypes.EffectivePolicyTypeTagPolicy, "222222222222", mock)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestParseTagPolicy(t *testing.T) {
+	effective := `{"tags":{"costcenter":{"tag_key":"CostCenter","tag_value":["100","200"],"enforced_for":["ec2:instance"]},"env":{"tag_key":"Environment"}}}`
+	rules, err := ParseTagPolicy(effective)
+	require.NoError(t, err)
+	assert.Equal(t, []TagPolicyRule{
+		{Key: "CostCenter", AllowedValues: []string{"100", "200"}, EnforcedFor: []string{"ec2:instance"}},
+		{Key: "Environment"},
+	}, rules)
+	assert.True(t, rules[0].IsEnforced())
+	assert.False(t, rules[1].IsEnforced())
+
+	// Attached policies use inheritance operators
+	attached := `{"tags":{"costcenter":{"tag_key":{"@@assign":"CostCenter"},"tag_value":{"@@assign":["100"]}}}}`
+	rules, err = ParseTagPolicy(attached)
+	require.NoError(t, err)
+	assert.Equal(t, []TagPolicyRule{{Key: "CostCenter", AllowedValues: []string{"100"}}}, rules)
+
+	_, err = ParseTagPolicy("not json")
+	assert.Error(t, err)
+}
+
+func TestParseBackupPolicy(t *testing.T) {
+	content := `{"plans":{"Daily":{"regions":["eu-west-1"],"rules":{"Nightly":{"schedule_expression":"cron(0 5 ? * * *)","target_backup_vault_name":"Default"}},"selections":{"tags":{"all":{"iam_role_arn":"arn:aws:iam::$account:role/Backup","tag_key":"backup","tag_value":["true","yes"]}}}}}}`
+	plans, err := ParseBackupPolicy(content)
+	require.NoError(t, err)
+	assert.Equal(t, []BackupPlan{{
+		Name:       "Daily",
+		Regions:    []string{"eu-west-1"},
+		Rules:      []BackupPlanRule{{Name: "Nightly", Schedule: "cron(0 5 ? * * *)", TargetVault: "Default"}},
+		Selections: []string{"backup=true|yes"},
+	}}, plans)
+}
+
+func TestParseAIServicesOptOutPolicy(t *testing.T) {
+	content := `{"services":{"rekognition":{"opt_out_policy":"optIn"},"default":{"opt_out_policy":"optOut"},"lex":{"opt_out_policy":"optOut"}}}`
+	settings, err := ParseAIServicesOptOutPolicy(content)
+	require.NoError(t, err)
+	assert.Equal(t, []AIServiceOptOut{
+		{Service: "default", Setting: "optOut"},
+		{Service: "lex", Setting: "optOut"},
+		{Service: "rekognition", Setting: "optIn"},
+	}, settings)
+}
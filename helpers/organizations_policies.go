@@ -0,0 +1,192 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/organizations/types"
+)
+
+// OrganizationsPolicyAPI defines the subset of the Organizations client used
+// to retrieve organization policies and their targets
+type OrganizationsPolicyAPI interface {
+	organizations.ListPoliciesAPIClient
+	organizations.ListTargetsForPolicyAPIClient
+	DescribePolicy(ctx context.Context, params *organizations.DescribePolicyInput, optFns ...func(*organizations.Options)) (*organizations.DescribePolicyOutput, error)
+}
+
+// OrganizationPolicy is an organization policy, such as an SCP, with its
+// content and the roots, OUs, and accounts it is attached to
+type OrganizationPolicy struct {
+	ID          string
+	Name        string
+	Arn         string
+	Description string
+	Type        string
+	AWSManaged  bool
+	Content     string
+	Targets     []OrganizationPolicyTarget
+}
+
+// OrganizationPolicyTarget is a root, OU, or account a policy is attached to
+type OrganizationPolicyTarget struct {
+	ID   string
+	Name string
+	Type string
+}
+
+// IsAttachedTo returns whether the policy is directly attached to the target
+func (policy OrganizationPolicy) IsAttachedTo(targetID string) bool {
+	for _, target := range policy.Targets {
+		if target.ID == targetID {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTargetNames returns the names of the policy's targets
+func (policy OrganizationPolicy) GetTargetNames() []string {
+	result := make([]string, 0, len(policy.Targets))
+	for _, target := range policy.Targets {
+		result = append(result, fmt.Sprintf("%s (%s)", target.Name, target.ID))
+	}
+	return result
+}
+
+// OrganizationPolicyLevel is a level in the path from the root to an
+// account, with the policies attached directly to it
+type OrganizationPolicyLevel struct {
+	Entry    OrganizationEntry
+	Policies []OrganizationPolicy
+}
+
+// GetOrganizationPolicies returns all policies of the provided type in the
+// organization with their content and targets
+func GetOrganizationPolicies(policytype types.PolicyType, svc OrganizationsPolicyAPI) ([]OrganizationPolicy, error) {
+	var result []OrganizationPolicy
+	paginator := organizations.NewListPoliciesPaginator(svc, &organizations.ListPoliciesInput{Filter: policytype})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s policies: %w", policytype, err)
+		}
+		for _, summary := range page.Policies {
+			policy := OrganizationPolicy{
+				ID:          aws.ToString(summary.Id),
+				Name:        aws.ToString(summary.Name),
+				Arn:         aws.ToString(summary.Arn),
+				Description: aws.ToString(summary.Description),
+				Type:        string(summary.Type),
+				AWSManaged:  summary.AwsManaged,
+			}
+			details, err := svc.DescribePolicy(context.TODO(), &organizations.DescribePolicyInput{PolicyId: summary.Id})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe policy %s: %w", policy.ID, err)
+			}
+			if details.Policy != nil {
+				policy.Content = aws.ToString(details.Policy.Content)
+			}
+			targets, err := getOrganizationPolicyTargets(policy.ID, svc)
+			if err != nil {
+				return nil, err
+			}
+			policy.Targets = targets
+			result = append(result, policy)
+		}
+	}
+	return result, nil
+}
+
+func getOrganizationPolicyTargets(policyID string, svc organizations.ListTargetsForPolicyAPIClient) ([]OrganizationPolicyTarget, error) {
+	var result []OrganizationPolicyTarget
+	paginator := organizations.NewListTargetsForPolicyPaginator(svc, &organizations.ListTargetsForPolicyInput{PolicyId: aws.String(policyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list targets for policy %s: %w", policyID, err)
+		}
+		for _, target := range page.Targets {
+			result = append(result, OrganizationPolicyTarget{
+				ID:   aws.ToString(target.TargetId),
+				Name: aws.ToString(target.Name),
+				Type: string(target.Type),
+			})
+		}
+	}
+	return result, nil
+}
+
+// PathTo returns the entries from this entry down to the entry with the
+// provided ID, including both
+func (entry *OrganizationEntry) PathTo(id string) ([]OrganizationEntry, bool) {
+	if entry.ID == id {
+		return []OrganizationEntry{*entry}, true
+	}
+	for _, child := range entry.Children {
+		if path, ok := child.PathTo(id); ok {
+			return append([]OrganizationEntry{*entry}, path...), true
+		}
+	}
+	return nil, false
+}
+
+// GetPolicyInheritance returns every level from the root of the organization
+// down to the account, with the policies attached to each level
+func GetPolicyInheritance(organization OrganizationEntry, accountID string, policies []OrganizationPolicy) ([]OrganizationPolicyLevel, error) {
+	path, ok := organization.PathTo(accountID)
+	if !ok || path[len(path)-1].Type != string(types.TargetTypeAccount) {
+		return nil, fmt.Errorf("account %s is not part of the organization", accountID)
+	}
+	result := make([]OrganizationPolicyLevel, 0, len(path))
+	for _, entry := range path {
+		level := OrganizationPolicyLevel{Entry: entry}
+		for _, policy := range policies {
+			if policy.IsAttachedTo(entry.ID) {
+				level.Policies = append(level.Policies, policy)
+			}
+		}
+		result = append(result, level)
+	}
+	return result, nil
+}
+
+// ParseSCP parses the content of a service control policy
+func ParseSCP(policy OrganizationPolicy) (IAMPolicyDocument, error) {
+	return ParseIAMPolicyDocument(policy.Name, IAMPolicyTypeSCP, policy.Content)
+}
+
+// SCPStatementMatch is a statement of an SCP and the level of the
+// organization it is inherited from
+type SCPStatementMatch struct {
+	IAMStatementMatch
+	InheritedFrom OrganizationEntry
+}
+
+// GetEffectiveSCPDenies returns all Deny statements of the SCPs that apply
+// along the inheritance path. Any of these statements blocks the actions it
+// matches, regardless of the level it is attached to.
+func GetEffectiveSCPDenies(levels []OrganizationPolicyLevel) ([]SCPStatementMatch, error) {
+	var result []SCPStatementMatch
+	for _, level := range levels {
+		for _, policy := range level.Policies {
+			document, err := ParseSCP(policy)
+			if err != nil {
+				return nil, err
+			}
+			for index, statement := range document.Statement {
+				if !strings.EqualFold(statement.Effect, "Deny") {
+					continue
+				}
+				result = append(result, SCPStatementMatch{
+					IAMStatementMatch: IAMStatementMatch{PolicyName: policy.Name, PolicyType: IAMPolicyTypeSCP, Index: index, Statement: statement},
+					InheritedFrom:     level.Entry,
+				})
+			}
+		}
+	}
+	return result, nil
+}
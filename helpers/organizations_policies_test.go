@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgtypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockOrganizationsPolicyClient implements OrganizationsPolicyAPI for testing.
+type mockOrganizationsPolicyClient struct {
+	policies []orgtypes.PolicySummary
+	contents map[string]string
+	targets  map[string][]orgtypes.PolicyTargetSummary
+	filters  []orgtypes.PolicyType
+}
+
+func (m *mockOrganizationsPolicyClient) ListPolicies(_ context.Context, params *organizations.ListPoliciesInput, _ ...func(*organizations.Options)) (*organizations.ListPoliciesOutput, error) {
+	m.filters = append(m.filters, params.Filter)
+	return &organizations.ListPoliciesOutput{Policies: m.policies}, nil
+}
+
+func (m *mockOrganizationsPolicyClient) ListTargetsForPolicy(_ context.Context, params *organizations.ListTargetsForPolicyInput, _ ...func(*organizations.Options)) (*organizations.ListTargetsForPolicyOutput, error) {
+	return &organizations.ListTargetsForPolicyOutput{Targets: m.targets[aws.ToString(params.PolicyId)]}, nil
+}
+
+func (m *mockOrganizationsPolicyClient) DescribePolicy(_ context.Context, params *organizations.DescribePolicyInput, _ ...func(*organizations.Options)) (*organizations.DescribePolicyOutput, error) {
+	return &organizations.DescribePolicyOutput{Policy: &orgtypes.Policy{Content: aws.String(m.contents[aws.ToString(params.PolicyId)])}}, nil
+}
+
+func testOrganization() OrganizationEntry {
+	return OrganizationEntry{ID: "r-root", Name: "Root", Type: string(orgtypes.TargetTypeRoot), Children: []OrganizationEntry{
+		{ID: "ou-workloads", Name: "Workloads", Type: string(orgtypes.TargetTypeOrganizationalUnit), Children: []OrganizationEntry{
+			{ID: "ou-prod", Name: "Prod", Type: string(orgtypes.TargetTypeOrganizationalUnit), Children: []OrganizationEntry{
+				{ID: "111111111111", Name: "prod-app", Type: string(orgtypes.TargetTypeAccount)},
+			}},
+		}},
+		{ID: "222222222222", Name: "management", Type: string(orgtypes.TargetTypeAccount)},
+	}}
+}
+
+func testSCPs() []OrganizationPolicy {
+	return []OrganizationPolicy{
+		{ID: "p-full", Name: "FullAWSAccess", Content: `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`,
+			Targets: []OrganizationPolicyTarget{{ID: "r-root"}, {ID: "ou-workloads"}, {ID: "ou-prod"}, {ID: "111111111111"}}},
+		{ID: "p-region", Name: "DenyRegions", Content: `{"Statement":[{"Sid":"Regions","Effect":"Deny","NotAction":"iam:*","Resource":"*","Condition":{"StringNotEquals":{"aws:RequestedRegion":"eu-west-1"}}}]}`,
+			Targets: []OrganizationPolicyTarget{{ID: "ou-workloads"}}},
+		{ID: "p-prod", Name: "ProtectProd", Content: `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"},{"Effect":"Deny","Action":"cloudtrail:StopLogging","Resource":"*"}]}`,
+			Targets: []OrganizationPolicyTarget{{ID: "ou-prod"}}},
+	}
+}
+
+func TestGetOrganizationPolicies(t *testing.T) {
+	mock := &mockOrganizationsPolicyClient{
+		policies: []orgtypes.PolicySummary{{Id: aws.String("p-full"), Name: aws.String("FullAWSAccess"), AwsManaged: true, Type: orgtypes.PolicyTypeServiceControlPolicy}},
+		contents: map[string]string{"p-full": `{"Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`},
+		targets: map[string][]orgtypes.PolicyTargetSummary{"p-full": {
+			{TargetId: aws.String("r-root"), Name: aws.String("Root"), Type: orgtypes.TargetTypeRoot},
+		}},
+	}
+	policies, err := GetOrganizationPolicies(orgtypes.PolicyTypeServiceControlPolicy, mock)
+	require.NoError(t, err)
+	require.Len(t, policies, 1)
+	assert.Equal(t, []orgtypes.PolicyType{orgtypes.PolicyTypeServiceControlPolicy}, mock.filters)
+	assert.True(t, policies[0].AWSManaged)
+	assert.Equal(t, mock.contents["p-full"], policies[0].Content)
+	assert.Equal(t, []string{"Root (r-root)"}, policies[0].GetTargetNames())
+}
+
+func TestOrganizationEntry_PathTo(t *testing.T) {
+	organization := testOrganization()
+	path, ok := organization.PathTo("111111111111")
+	require.True(t, ok)
+	ids := make([]string, 0, len(path))
+	for _, entry := range path {
+		ids = append(ids, entry.ID)
+	}
+	assert.Equal(t, []string{"r-root", "ou-workloads", "ou-prod", "111111111111"}, ids)
+	_, ok = organization.PathTo("333333333333")
+	assert.False(t, ok)
+}
+
+func TestGetPolicyInheritance(t *testing.T) {
+	levels, err := GetPolicyInheritance(testOrganization(), "111111111111", testSCPs())
+	require.NoError(t, err)
+	require.Len(t, levels, 4)
+	names := func(level OrganizationPolicyLevel) []string {
+		var result []string
+		for _, policy := range level.Policies {
+			result = append(result, policy.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"FullAWSAccess"}, names(levels[0]))
+	assert.Equal(t, []string{"FullAWSAccess", "DenyRegions"}, names(levels[1]))
+	assert.Equal(t, []string{"FullAWSAccess", "ProtectProd"}, names(levels[2]))
+	assert.Equal(t, []string{"FullAWSAccess"}, names(levels[3]))
+
+	_, err = GetPolicyInheritance(testOrganization(), "ou-prod", testSCPs())
+	assert.Error(t, err, "an OU is not an account")
+}
+
+func TestGetEffectiveSCPDenies(t *testing.T) {
+	levels, err := GetPolicyInheritance(testOrganization(), "111111111111", testSCPs())
+	require.NoError(t, err)
+	denies, err := GetEffectiveSCPDenies(levels)
+	require.NoError(t, err)
+	require.Len(t, denies, 2)
+	assert.Equal(t, "DenyRegions (Service Control Policy) statement 1 (Sid: Regions)", denies[0].String())
+	assert.Equal(t, "Workloads", denies[0].InheritedFrom.Name)
+	assert.Equal(t, "ProtectProd", denies[1].PolicyName)
+	assert.Equal(t, 1, denies[1].Index)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/organizations"
@@ -97,6 +98,24 @@ func TestOrganizationEntry_WithChildren(t *testing.T) {
 
 // Regression tests for T-418: error handling must not panic
 
+func TestGetOrganizationRoot_EnabledPolicyTypes(t *testing.T) {
+	mock := &mockOrganizationsClient{
+		ListRootsFunc: func(_ context.Context, _ *organizations.ListRootsInput, _ ...func(*organizations.Options)) (*organizations.ListRootsOutput, error) {
+			return &organizations.ListRootsOutput{Roots: []orgtypes.Root{{
+				Id:   aws.String("r-root1"),
+				Name: aws.String("Root"),
+				PolicyTypes: []orgtypes.PolicyTypeSummary{
+					{Type: orgtypes.PolicyTypeServiceControlPolicy, Status: orgtypes.PolicyTypeStatusEnabled},
+					{Type: orgtypes.PolicyTypeTagPolicy, Status: orgtypes.PolicyTypeStatusPendingDisable},
+				},
+			}}}, nil
+		},
+	}
+	entry, err := getOrganizationRoot(mock)
+	require.NoError(t, err)
+	assert.Equal(t, []orgtypes.PolicyType{orgtypes.PolicyTypeServiceControlPolicy}, entry.PolicyTypes)
+}
+
 func TestGetOrganizationRoot_ListRootsError_ReturnsError(t *testing.T) {
 	mock := &mockOrganizationsClient{
 		ListRootsFunc: func(_ context.Context, _ *organizations.ListRootsInput, _ ...func(*organizations.Options)) (*organizations.ListRootsOutput, error) {
@@ -384,3 +403,86 @@ func TestFindChildren_DescribeOUErrorDuringTraversal_ReturnsError(t *testing.T)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "OU describe failed")
 }
+
+func TestGetFullOrganization_AccountMetadataAndPath(t *testing.T) {
+	joined := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock := &mockOrganizationsClient{
+		ListRootsFunc: func(_ context.Context, _ *organizations.ListRootsInput, _ ...func(*organizations.Options)) (*organizations.ListRootsOutput, error) {
+			return &organizations.ListRootsOutput{Roots: []orgtypes.Root{{Id: aws.String("r-root1"), Name: aws.String("Root")}}}, nil
+		},
+		ListChildrenFunc: func(_ context.Context, params *organizations.ListChildrenInput, _ ...func(*organizations.Options)) (*organizations.ListChildrenOutput, error) {
+			switch {
+			case *params.ParentId == "r-root1" && params.ChildType == orgtypes.ChildType(orgtypes.TargetTypeOrganizationalUnit):
+				return &organizations.ListChildrenOutput{Children: []orgtypes.Child{{Id: aws.String("ou-prod1"), Type: orgtypes.ChildType(orgtypes.TargetTypeOrganizationalUnit)}}}, nil
+			case *params.ParentId == "ou-prod1" && params.ChildType == orgtypes.ChildType(orgtypes.TargetTypeAccount):
+				return &organizations.ListChildrenOutput{Children: []orgtypes.Child{{Id: aws.String("111111111111"), Type: orgtypes.ChildType(orgtypes.TargetTypeAccount)}}}, nil
+			}
+			return &organizations.ListChildrenOutput{}, nil
+		},
+		DescribeOrganizationalUnitFunc: func(_ context.Context, params *organizations.DescribeOrganizationalUnitInput, _ ...func(*organizations.Options)) (*organizations.DescribeOrganizationalUnitOutput, error) {
+			return &organizations.DescribeOrganizationalUnitOutput{OrganizationalUnit: &orgtypes.OrganizationalUnit{Id: params.OrganizationalUnitId, Arn: aws.String("arn"), Name: aws.String("Production")}}, nil
+		},
+		DescribeAccountFunc: func(_ context.Context, params *organizations.DescribeAccountInput, _ ...func(*organizations.Options)) (*organizations.DescribeAccountOutput, error) {
+			return &organizations.DescribeAccountOutput{Account: &orgtypes.Account{
+				Id:              params.AccountId,
+				Arn:             aws.String("arn"),
+				Name:            aws.String("prod-app"),
+				Email:           aws.String("prod@example.com"),
+				Status:          orgtypes.AccountStatusSuspended,
+				JoinedMethod:    orgtypes.AccountJoinedMethodCreated,
+				JoinedTimestamp: aws.Time(joined),
+			}}, nil
+		},
+	}
+
+	org, err := GetFullOrganization(mock)
+	require.NoError(t, err)
+	require.Len(t, org.Children, 1)
+	ou := org.Children[0]
+	assert.Equal(t, "Root", ou.Path)
+	require.Len(t, ou.Children, 1)
+	account := ou.Children[0]
+	assert.Equal(t, "Root/Production", account.Path)
+	assert.Equal(t, "prod@example.com", account.Email)
+	assert.Equal(t, "CREATED", account.JoinedMethod)
+	assert.Equal(t, joined, account.JoinedTimestamp)
+	assert.True(t, account.IsSuspended())
+	assert.Equal(t, "prod-app (111111111111) [SUSPENDED]", account.DisplayName())
+	assert.Equal(t, "Production (ou-prod1)", ou.DisplayName())
+}
+
+func TestOrganizationEntry_Tree(t *testing.T) {
+	root := OrganizationEntry{Name: "Root", ID: "r-1", Children: []OrganizationEntry{
+		{Name: "Prod", ID: "ou-1", Children: []OrganizationEntry{
+			{Name: "app", ID: "111", Status: "ACTIVE"},
+			{Name: "old", ID: "222", Status: "SUSPENDED"},
+		}},
+		{Name: "management", ID: "333", Status: "ACTIVE"},
+	}}
+	want := `Root (r-1)
+├── Prod (ou-1)
+│   ├── app (111)
+│   └── old (222) [SUSPENDED]
+└── management (333)
+`
+	assert.Equal(t, want, root.Tree())
+}
+
+type mockOrganizationsTagsClient struct {
+	tags map[string][]orgtypes.Tag
+}
+
+func (m *mockOrganizationsTagsClient) ListTagsForResource(_ context.Context, params *organizations.ListTagsForResourceInput, _ ...func(*organizations.Options)) (*organizations.ListTagsForResourceOutput, error) {
+	return &organizations.ListTagsForResourceOutput{Tags: m.tags[*params.ResourceId]}, nil
+}
+
+func TestOrganizationEntry_AddTags(t *testing.T) {
+	root := OrganizationEntry{ID: "r-1", Children: []OrganizationEntry{{ID: "111"}}}
+	mock := &mockOrganizationsTagsClient{tags: map[string][]orgtypes.Tag{
+		"111": {{Key: aws.String("CostCenter"), Value: aws.String("42")}},
+	}}
+	require.NoError(t, root.AddTags(mock))
+	assert.Empty(t, root.Tags)
+	assert.Equal(t, map[string]string{"CostCenter": "42"}, root.Children[0].Tags)
+	assert.Equal(t, []string{"CostCenter=42"}, root.Children[0].GetTagList())
+}
@@ -53,14 +53,20 @@ type SSOPermissionSet struct {
 	Accounts        []SSOAccount
 	ManagedPolicies []SSOPolicy
 	InlinePolicy    string
+	RelayState      string
 	Instance        *SSOInstance
+	// CustomerManagedPolicies, PermissionsBoundary, and Tags are only set after calling AddDetails
+	CustomerManagedPolicies []SSOCustomerManagedPolicy
+	PermissionsBoundary     *SSOPermissionsBoundary
+	Tags                    map[string]string
 }
 
 // SSOPolicy represents a Managed Policy
 type SSOPolicy struct {
 	Arn  string
 	Name string
-	// Policy string
+	// Policy is the policy document, which is only set after calling AddPolicyDocuments
+	Policy string
 }
 
 // SSOAccount represents an AWS account managed by AWS
@@ -160,6 +166,7 @@ func (instance *SSOInstance) getPermissionSetDetails(permissionsetarn string, sv
 	if ps.Description != nil {
 		permissionset.Description = *ps.Description
 	}
+	permissionset.RelayState = aws.ToString(ps.RelayState)
 	// Get accounts
 	if err := permissionset.addAccountInfo(svc); err != nil {
 		return SSOPermissionSet{}, err
@@ -328,6 +335,16 @@ func (account *SSOAccount) GetPrincipalIDsForPermissionSet(permissionset SSOPerm
 	return accountchildren
 }
 
+// GetPermissionSet returns the permission set with the provided name or ARN
+func (instance *SSOInstance) GetPermissionSet(nameOrArn string) (SSOPermissionSet, bool) {
+	for _, permissionset := range instance.PermissionSets {
+		if permissionset.Name == nameOrArn || permissionset.Arn == nameOrArn {
+			return permissionset, true
+		}
+	}
+	return SSOPermissionSet{}, false
+}
+
 // GetManagedPolicyNames returns a slice containing the names of the policies attached to the permission set
 func (permissionset *SSOPermissionSet) GetManagedPolicyNames() []string {
 	policynames := []string{}
@@ -0,0 +1,83 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+)
+
+// SSOAccess is a user's access to an account through a permission set,
+// either assigned directly or through a group
+type SSOAccess struct {
+	User          SSOPrincipal
+	AccountID     string
+	PermissionSet *SSOPermissionSet
+	// Group is the group the access is assigned to, or nil for direct assignments
+	Group *SSOPrincipal
+}
+
+// IsDirect returns whether the access is assigned directly to the user
+func (access SSOAccess) IsDirect() bool {
+	return access.Group == nil
+}
+
+// Via returns how the user gets the access, using the name function to show
+// the group
+func (access SSOAccess) Via(name func(SSOPrincipal) string) string {
+	if access.IsDirect() {
+		return "Direct"
+	}
+	return "Group " + name(*access.Group)
+}
+
+// Matches returns whether the ID, user name, display name, or email of the
+// principal equals the value, ignoring case
+func (principal SSOPrincipal) Matches(value string) bool {
+	for _, field := range []string{principal.ID, principal.UserName, principal.DisplayName, principal.Email} {
+		if field != "" && strings.EqualFold(field, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAccessMatrix expands the account assignments of the instance into the
+// access every user has, replacing group assignments with an entry for each
+// member of the group. The result is sorted by user, account, and permission
+// set.
+func (instance *SSOInstance) GetAccessMatrix(resolver *SSOPrincipalResolver) ([]SSOAccess, error) {
+	var result []SSOAccess
+	for _, account := range instance.Accounts {
+		for _, assignment := range account.AccountAssignments {
+			principal, err := resolver.Resolve(assignment.PrincipalType, assignment.PrincipalID)
+			if err != nil {
+				return nil, err
+			}
+			if assignment.PrincipalType != string(ssotypes.PrincipalTypeGroup) {
+				result = append(result, SSOAccess{User: principal, AccountID: account.AccountID, PermissionSet: assignment.PermissionSet})
+				continue
+			}
+			members, err := resolver.GetGroupMembers(assignment.PrincipalID)
+			if err != nil {
+				return nil, err
+			}
+			for _, member := range members {
+				result = append(result, SSOAccess{User: member, AccountID: account.AccountID, PermissionSet: assignment.PermissionSet, Group: &principal})
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].User.Name() != result[j].User.Name() {
+			return strings.ToLower(result[i].User.Name()) < strings.ToLower(result[j].User.Name())
+		}
+		if result[i].AccountID != result[j].AccountID {
+			return result[i].AccountID < result[j].AccountID
+		}
+		if result[i].PermissionSet.Name != result[j].PermissionSet.Name {
+			return result[i].PermissionSet.Name < result[j].PermissionSet.Name
+		}
+		return result[i].Via(SSOPrincipal.Name) < result[j].Via(SSOPrincipal.Name)
+	})
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSOInstance_GetAccessMatrix(t *testing.T) {
+	admin := &SSOPermissionSet{Name: "AdministratorAccess"}
+	readonly := &SSOPermissionSet{Name: "ReadOnly"}
+	instance := SSOInstance{Accounts: map[string]SSOAccount{
+		"222222222222": {AccountID: "222222222222", AccountAssignments: []SSOAccountAssignment{
+			{PrincipalType: "GROUP", PrincipalID: "g-admins", PermissionSet: admin},
+		}},
+		"111111111111": {AccountID: "111111111111", AccountAssignments: []SSOAccountAssignment{
+			{PrincipalType: "USER", PrincipalID: "u-jane", PermissionSet: readonly},
+			{PrincipalType: "GROUP", PrincipalID: "g-admins", PermissionSet: admin},
+		}},
+	}}
+	matrix, err := instance.GetAccessMatrix(NewSSOPrincipalResolver(instance, testIdentityStore()))
+	require.NoError(t, err)
+
+	got := make([][]string, 0, len(matrix))
+	for _, access := range matrix {
+		got = append(got, []string{access.User.Name(), access.AccountID, access.PermissionSet.Name, access.Via(SSOPrincipal.Name)})
+	}
+	assert.Equal(t, [][]string{
+		{"bob", "111111111111", "AdministratorAccess", "Group Admins"},
+		{"bob", "222222222222", "AdministratorAccess", "Group Admins"},
+		{"Jane Doe", "111111111111", "AdministratorAccess", "Group Admins"},
+		{"Jane Doe", "111111111111", "ReadOnly", "Direct"},
+		{"Jane Doe", "222222222222", "AdministratorAccess", "Group Admins"},
+	}, got)
+	assert.True(t, matrix[3].IsDirect())
+}
+
+func TestSSOPrincipal_Matches(t *testing.T) {
+	principal := SSOPrincipal{ID: "u-jane", UserName: "jane", DisplayName: "Jane Doe", Email: "jane@example.com"}
+	for _, value := range []string{"u-jane", "JANE", "jane doe", "jane@example.com"} {
+		assert.True(t, principal.Matches(value), value)
+	}
+	assert.False(t, principal.Matches("bob"))
+	assert.False(t, SSOPrincipal{ID: "u-bob"}.Matches(""))
+}
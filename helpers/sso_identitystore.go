@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	idstoretypes "github.com/aws/aws-sdk-go-v2/service/identitystore/types"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+)
+
+// IdentityStoreAPI defines the subset of the Identity Store client used to
+// resolve SSO principals and group members
+type IdentityStoreAPI interface {
+	DescribeUser(ctx context.Context, params *identitystore.DescribeUserInput, optFns ...func(*identitystore.Options)) (*identitystore.DescribeUserOutput, error)
+	DescribeGroup(ctx context.Context, params *identitystore.DescribeGroupInput, optFns ...func(*identitystore.Options)) (*identitystore.DescribeGroupOutput, error)
+	identitystore.ListGroupMembershipsAPIClient
+}
+
+// SSOPrincipal is a user or group in the Identity Store
+type SSOPrincipal struct {
+	ID          string
+	Type        string
+	DisplayName string
+	// UserName and Email are only set for users
+	UserName string
+	Email    string
+}
+
+// Name returns the display name of the principal, falling back to the user
+// name and then the ID
+func (principal SSOPrincipal) Name() string {
+	switch {
+	case principal.DisplayName != "":
+		return principal.DisplayName
+	case principal.UserName != "":
+		return principal.UserName
+	}
+	return principal.ID
+}
+
+// SSOPrincipalResolver looks up users and groups in the Identity Store of an
+// SSO instance, caching every lookup
+type SSOPrincipalResolver struct {
+	identityStoreID string
+	svc             IdentityStoreAPI
+	cache           map[string]SSOPrincipal
+	members         map[string][]SSOPrincipal
+}
+
+// NewSSOPrincipalResolver returns a resolver for the Identity Store of the
+// SSO instance
+func NewSSOPrincipalResolver(instance SSOInstance, svc IdentityStoreAPI) *SSOPrincipalResolver {
+	return &SSOPrincipalResolver{
+		identityStoreID: instance.IdentityStoreID,
+		svc:             svc,
+		cache:           make(map[string]SSOPrincipal),
+		members:         make(map[string][]SSOPrincipal),
+	}
+}
+
+// Resolve returns the user or group with the provided ID. Principals that no
+// longer exist in the Identity Store are returned with only their ID and type.
+func (resolver *SSOPrincipalResolver) Resolve(principalType string, principalID string) (SSOPrincipal, error) {
+	if principal, ok := resolver.cache[principalID]; ok {
+		return principal, nil
+	}
+	principal := SSOPrincipal{ID: principalID, Type: principalType}
+	var err error
+	switch principalType {
+	case string(ssotypes.PrincipalTypeUser):
+		err = resolver.describeUser(&principal)
+	case string(ssotypes.PrincipalTypeGroup):
+		err = resolver.describeGroup(&principal)
+	default:
+		return principal, fmt.Errorf("unknown principal type %s for principal %s", principalType, principalID)
+	}
+	var notFound *idstoretypes.ResourceNotFoundException
+	if err != nil && !errors.As(err, &notFound) {
+		return principal, err
+	}
+	resolver.cache[principalID] = principal
+	return principal, nil
+}
+
+func (resolver *SSOPrincipalResolver) describeUser(principal *SSOPrincipal) error {
+	user, err := resolver.svc.DescribeUser(context.TODO(), &identitystore.DescribeUserInput{
+		IdentityStoreId: aws.String(resolver.identityStoreID),
+		UserId:          aws.String(principal.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe user %s: %w", principal.ID, err)
+	}
+	principal.DisplayName = aws.ToString(user.DisplayName)
+	principal.UserName = aws.ToString(user.UserName)
+	for _, email := range user.Emails {
+		if principal.Email == "" || email.Primary {
+			principal.Email = aws.ToString(email.Value)
+		}
+	}
+	return nil
+}
+
+func (resolver *SSOPrincipalResolver) describeGroup(principal *SSOPrincipal) error {
+	group, err := resolver.svc.DescribeGroup(context.TODO(), &identitystore.DescribeGroupInput{
+		IdentityStoreId: aws.String(resolver.identityStoreID),
+		GroupId:         aws.String(principal.ID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe group %s: %w", principal.ID, err)
+	}
+	principal.DisplayName = aws.ToString(group.DisplayName)
+	return nil
+}
+
+// GetGroupMembers returns the users that are a member of the group. A group
+// that no longer exists has no members.
+func (resolver *SSOPrincipalResolver) GetGroupMembers(groupID string) ([]SSOPrincipal, error) {
+	if members, ok := resolver.members[groupID]; ok {
+		return members, nil
+	}
+	var result []SSOPrincipal
+	paginator := identitystore.NewListGroupMembershipsPaginator(resolver.svc, &identitystore.ListGroupMembershipsInput{
+		IdentityStoreId: aws.String(resolver.identityStoreID),
+		GroupId:         aws.String(groupID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		var notFound *idstoretypes.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list members of group %s: %w", groupID, err)
+		}
+		for _, membership := range page.GroupMemberships {
+			member, ok := membership.MemberId.(*idstoretypes.MemberIdMemberUserId)
+			if !ok {
+				continue
+			}
+			user, err := resolver.Resolve(string(ssotypes.PrincipalTypeUser), member.Value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, user)
+		}
+	}
+	resolver.members[groupID] = result
+	return result, nil
+}
+
+// GetPrincipals resolves every principal with an account assignment in the
+// instance and returns them keyed by principal ID
+func (instance *SSOInstance) GetPrincipals(resolver *SSOPrincipalResolver) (map[string]SSOPrincipal, error) {
+	result := make(map[string]SSOPrincipal)
+	for _, account := range instance.Accounts {
+		for _, assignment := range account.AccountAssignments {
+			if _, ok := result[assignment.PrincipalID]; ok {
+				continue
+			}
+			principal, err := resolver.Resolve(assignment.PrincipalType, assignment.PrincipalID)
+			if err != nil {
+				return nil, err
+			}
+			result[assignment.PrincipalID] = principal
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,145 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/identitystore"
+	idstoretypes "github.com/aws/aws-sdk-go-v2/service/identitystore/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdentityStoreClient implements IdentityStoreAPI for testing.
+type mockIdentityStoreClient struct {
+	users   map[string]*identitystore.DescribeUserOutput
+	groups  map[string]*identitystore.DescribeGroupOutput
+	members map[string][]string
+	calls   int
+}
+
+func (m *mockIdentityStoreClient) DescribeUser(_ context.Context, params *identitystore.DescribeUserInput, _ ...func(*identitystore.Options)) (*identitystore.DescribeUserOutput, error) {
+	m.calls++
+	if user, ok := m.users[aws.ToString(params.UserId)]; ok {
+		return user, nil
+	}
+	return nil, &idstoretypes.ResourceNotFoundException{Message: aws.String("user not found")}
+}
+
+func (m *mockIdentityStoreClient) DescribeGroup(_ context.Context, params *identitystore.DescribeGroupInput, _ ...func(*identitystore.Options)) (*identitystore.DescribeGroupOutput, error) {
+	m.calls++
+	if group, ok := m.groups[aws.ToString(params.GroupId)]; ok {
+		return group, nil
+	}
+	return nil, errors.New("access denied")
+}
+
+func (m *mockIdentityStoreClient) ListGroupMemberships(_ context.Context, params *identitystore.ListGroupMembershipsInput, _ ...func(*identitystore.Options)) (*identitystore.ListGroupMembershipsOutput, error) {
+	m.calls++
+	members, ok := m.members[aws.ToString(params.GroupId)]
+	if !ok {
+		return nil, &idstoretypes.ResourceNotFoundException{Message: aws.String("group not found")}
+	}
+	output := &identitystore.ListGroupMembershipsOutput{}
+	for _, member := range members {
+		output.GroupMemberships = append(output.GroupMemberships, idstoretypes.GroupMembership{GroupId: params.GroupId, MemberId: &idstoretypes.MemberIdMemberUserId{Value: member}})
+	}
+	return output, nil
+}
+
+func testIdentityStore() *mockIdentityStoreClient {
+	return &mockIdentityStoreClient{
+		users: map[string]*identitystore.DescribeUserOutput{
+			"u-jane": {
+				DisplayName: aws.String("Jane Doe"),
+				UserName:    aws.String("jane"),
+				Emails: []idstoretypes.Email{
+					{Value: aws.String("jane@personal.example.com")},
+					{Value: aws.String("jane@example.com"), Primary: true},
+				},
+			},
+			"u-bob": {UserName: aws.String("bob")},
+		},
+		groups: map[string]*identitystore.DescribeGroupOutput{
+			"g-admins": {DisplayName: aws.String("Admins")},
+		},
+		members: map[string][]string{
+			"g-admins": {"u-jane", "u-bob"},
+		},
+	}
+}
+
+func TestSSOPrincipalResolver_Resolve(t *testing.T) {
+	mock := testIdentityStore()
+	resolver := NewSSOPrincipalResolver(SSOInstance{IdentityStoreID: "d-123"}, mock)
+
+	jane, err := resolver.Resolve("USER", "u-jane")
+	require.NoError(t, err)
+	assert.Equal(t, SSOPrincipal{ID: "u-jane", Type: "USER", DisplayName: "Jane Doe", UserName: "jane", Email: "jane@example.com"}, jane)
+	assert.Equal(t, "Jane Doe", jane.Name())
+
+	bob, err := resolver.Resolve("USER", "u-bob")
+	require.NoError(t, err)
+	assert.Equal(t, "bob", bob.Name())
+
+	admins, err := resolver.Resolve("GROUP", "g-admins")
+	require.NoError(t, err)
+	assert.Equal(t, "Admins", admins.Name())
+
+	// Deleted principals fall back to their ID
+	deleted, err := resolver.Resolve("USER", "u-deleted")
+	require.NoError(t, err)
+	assert.Equal(t, "u-deleted", deleted.Name())
+
+	// Lookups are cached
+	calls := mock.calls
+	_, err = resolver.Resolve("USER", "u-jane")
+	require.NoError(t, err)
+	_, err = resolver.Resolve("USER", "u-deleted")
+	require.NoError(t, err)
+	assert.Equal(t, calls, mock.calls)
+
+	_, err = resolver.Resolve("GROUP", "g-unknown")
+	assert.ErrorContains(t, err, "failed to describe group g-unknown")
+}
+
+func TestSSOInstance_GetPrincipals(t *testing.T) {
+	instance := SSOInstance{Accounts: map[string]SSOAccount{
+		"111111111111": {AccountID: "111111111111", AccountAssignments: []SSOAccountAssignment{
+			{PrincipalType: "USER", PrincipalID: "u-jane"},
+			{PrincipalType: "GROUP", PrincipalID: "g-admins"},
+		}},
+		"222222222222": {AccountID: "222222222222", AccountAssignments: []SSOAccountAssignment{
+			{PrincipalType: "USER", PrincipalID: "u-jane"},
+		}},
+	}}
+	mock := testIdentityStore()
+	principals, err := instance.GetPrincipals(NewSSOPrincipalResolver(instance, mock))
+	require.NoError(t, err)
+	require.Len(t, principals, 2)
+	assert.Equal(t, "Jane Doe", principals["u-jane"].Name())
+	assert.Equal(t, "Admins", principals["g-admins"].Name())
+	assert.Equal(t, 2, mock.calls)
+}
+
+func TestSSOPrincipalResolver_GetGroupMembers(t *testing.T) {
+	mock := testIdentityStore()
+	resolver := NewSSOPrincipalResolver(SSOInstance{IdentityStoreID: "d-123"}, mock)
+	members, err := resolver.GetGroupMembers("g-admins")
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+	assert.Equal(t, "Jane Doe", members[0].Name())
+	assert.Equal(t, "bob", members[1].Name())
+
+	// Memberships are cached
+	calls := mock.calls
+	_, err = resolver.GetGroupMembers("g-admins")
+	require.NoError(t, err)
+	assert.Equal(t, calls, mock.calls)
+
+	members, err = resolver.GetGroupMembers("g-deleted")
+	require.NoError(t, err)
+	assert.Empty(t, members)
+}
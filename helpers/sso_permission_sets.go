@@ -0,0 +1,207 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+)
+
+// SSOPermissionSetDetailsAPI defines the SSO Admin calls used to retrieve
+// the parts of a permission set that aren't needed for the overviews
+type SSOPermissionSetDetailsAPI interface {
+	ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetAPIClient
+	ssoadmin.ListTagsForResourceAPIClient
+	GetPermissionsBoundaryForPermissionSet(ctx context.Context, params *ssoadmin.GetPermissionsBoundaryForPermissionSetInput, optFns ...func(*ssoadmin.Options)) (*ssoadmin.GetPermissionsBoundaryForPermissionSetOutput, error)
+}
+
+// SSOPermissionSetPoliciesAPI defines the SSO Admin calls used to retrieve a
+// permission set with all of its policies
+type SSOPermissionSetPoliciesAPI interface {
+	SSOAdminAPI
+	SSOPermissionSetDetailsAPI
+}
+
+// SSOCustomerManagedPolicy is a reference to a customer managed policy that
+// needs to exist with the same name and path in every account the
+// permission set is provisioned to
+type SSOCustomerManagedPolicy struct {
+	Name string
+	Path string
+	// Policy is the policy document, which is only set after calling
+	// AddPolicyDocuments and if the policy exists in that account
+	Policy string
+}
+
+// ArnInAccount returns the ARN the customer managed policy has in the account
+func (policy SSOCustomerManagedPolicy) ArnInAccount(partition string, accountID string) string {
+	path := policy.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:policy%s%s", partition, accountID, path, policy.Name)
+}
+
+// SSOPermissionsBoundary is the permissions boundary of a permission set,
+// which is either an AWS managed policy or a customer managed policy
+type SSOPermissionsBoundary struct {
+	ManagedPolicyArn      string
+	CustomerManagedPolicy *SSOCustomerManagedPolicy
+	// Policy is the policy document, which is only set after calling AddPolicyDocuments
+	Policy string
+}
+
+// Name returns the ARN of the AWS managed policy or the path and name of the
+// customer managed policy
+func (boundary SSOPermissionsBoundary) Name() string {
+	if boundary.CustomerManagedPolicy != nil {
+		path := boundary.CustomerManagedPolicy.Path
+		if path == "" {
+			path = "/"
+		}
+		return path + boundary.CustomerManagedPolicy.Name
+	}
+	return boundary.ManagedPolicyArn
+}
+
+// Partition returns the partition of the permission set's ARN
+func (permissionset *SSOPermissionSet) Partition() string {
+	parts := strings.Split(permissionset.Arn, ":")
+	if len(parts) < 2 || parts[1] == "" {
+		return "aws"
+	}
+	return parts[1]
+}
+
+// AddDetails retrieves the customer managed policy references, the
+// permissions boundary, and the tags of the permission set
+func (permissionset *SSOPermissionSet) AddDetails(svc SSOPermissionSetDetailsAPI) error {
+	instanceArn := aws.String(permissionset.Instance.Arn)
+	permissionset.CustomerManagedPolicies = nil
+	references := ssoadmin.NewListCustomerManagedPolicyReferencesInPermissionSetPaginator(svc, &ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetInput{
+		InstanceArn:      instanceArn,
+		PermissionSetArn: aws.String(permissionset.Arn),
+	})
+	for references.HasMorePages() {
+		page, err := references.NextPage(context.TODO())
+		if err != nil {
+			return fmt.Errorf("failed to list customer managed policies for permission set %s: %w", permissionset.Arn, err)
+		}
+		for _, reference := range page.CustomerManagedPolicyReferences {
+			permissionset.CustomerManagedPolicies = append(permissionset.CustomerManagedPolicies, SSOCustomerManagedPolicy{
+				Name: aws.ToString(reference.Name),
+				Path: aws.ToString(reference.Path),
+			})
+		}
+	}
+	boundary, err := svc.GetPermissionsBoundaryForPermissionSet(context.TODO(), &ssoadmin.GetPermissionsBoundaryForPermissionSetInput{
+		InstanceArn:      instanceArn,
+		PermissionSetArn: aws.String(permissionset.Arn),
+	})
+	var notFound *ssotypes.ResourceNotFoundException
+	switch {
+	case errors.As(err, &notFound):
+		permissionset.PermissionsBoundary = nil
+	case err != nil:
+		return fmt.Errorf("failed to get permissions boundary for permission set %s: %w", permissionset.Arn, err)
+	case boundary.PermissionsBoundary != nil:
+		permissionset.PermissionsBoundary = &SSOPermissionsBoundary{ManagedPolicyArn: aws.ToString(boundary.PermissionsBoundary.ManagedPolicyArn)}
+		if reference := boundary.PermissionsBoundary.CustomerManagedPolicyReference; reference != nil {
+			permissionset.PermissionsBoundary.CustomerManagedPolicy = &SSOCustomerManagedPolicy{Name: aws.ToString(reference.Name), Path: aws.ToString(reference.Path)}
+		}
+	}
+	permissionset.Tags = make(map[string]string)
+	tags := ssoadmin.NewListTagsForResourcePaginator(svc, &ssoadmin.ListTagsForResourceInput{
+		InstanceArn: instanceArn,
+		ResourceArn: aws.String(permissionset.Arn),
+	})
+	for tags.HasMorePages() {
+		page, err := tags.NextPage(context.TODO())
+		if err != nil {
+			return fmt.Errorf("failed to list tags for permission set %s: %w", permissionset.Arn, err)
+		}
+		for _, tag := range page.Tags {
+			permissionset.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return nil
+}
+
+// AddPolicyDocuments retrieves the documents of the AWS managed policies,
+// and of the customer managed policies and permissions boundary as they
+// exist in the provided account. The IAM client needs to belong to that
+// account. Customer managed policies that don't exist in the account are
+// left without a document.
+func (permissionset *SSOPermissionSet) AddPolicyDocuments(accountID string, svc IAMClient) error {
+	// The managed policies are shared with the permission set in the instance
+	permissionset.ManagedPolicies = slices.Clone(permissionset.ManagedPolicies)
+	for i, policy := range permissionset.ManagedPolicies {
+		document, err := getManagedPolicyDocument(policy.Arn, svc)
+		if err != nil {
+			return fmt.Errorf("failed to get managed policy %s: %w", policy.Arn, err)
+		}
+		permissionset.ManagedPolicies[i].Policy = document
+	}
+	for i, policy := range permissionset.CustomerManagedPolicies {
+		document, err := getCustomerManagedPolicyDocument(policy.ArnInAccount(permissionset.Partition(), accountID), svc)
+		if err != nil {
+			return err
+		}
+		permissionset.CustomerManagedPolicies[i].Policy = document
+	}
+	boundary := permissionset.PermissionsBoundary
+	if boundary == nil {
+		return nil
+	}
+	if boundary.CustomerManagedPolicy != nil {
+		document, err := getCustomerManagedPolicyDocument(boundary.CustomerManagedPolicy.ArnInAccount(permissionset.Partition(), accountID), svc)
+		if err != nil {
+			return err
+		}
+		boundary.CustomerManagedPolicy.Policy = document
+		boundary.Policy = document
+		return nil
+	}
+	document, err := getManagedPolicyDocument(boundary.ManagedPolicyArn, svc)
+	if err != nil {
+		return fmt.Errorf("failed to get managed policy %s: %w", boundary.ManagedPolicyArn, err)
+	}
+	boundary.Policy = document
+	return nil
+}
+
+// MissingCustomerManagedPolicies returns the names of the customer managed
+// policies, including the permissions boundary, that didn't exist in the
+// account used for AddPolicyDocuments
+func (permissionset *SSOPermissionSet) MissingCustomerManagedPolicies() []string {
+	var result []string
+	for _, policy := range permissionset.CustomerManagedPolicies {
+		if policy.Policy == "" {
+			result = append(result, policy.Name)
+		}
+	}
+	if boundary := permissionset.PermissionsBoundary; boundary != nil && boundary.CustomerManagedPolicy != nil && boundary.Policy == "" {
+		result = append(result, boundary.CustomerManagedPolicy.Name)
+	}
+	return result
+}
+
+// getCustomerManagedPolicyDocument returns the document of the policy, or an
+// empty string if it doesn't exist
+func getCustomerManagedPolicyDocument(policyArn string, svc IAMClient) (string, error) {
+	document, err := getManagedPolicyDocument(policyArn, svc)
+	var notFound *iamtypes.NoSuchEntityException
+	if errors.As(err, &notFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get customer managed policy %s: %w", policyArn, err)
+	}
+	return document, nil
+}
@@ -0,0 +1,136 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSSOPermissionSetDetailsClient implements SSOPermissionSetDetailsAPI for testing.
+type mockSSOPermissionSetDetailsClient struct {
+	references []ssotypes.CustomerManagedPolicyReference
+	boundary   *ssotypes.PermissionsBoundary
+	tags       []ssotypes.Tag
+}
+
+func (m *mockSSOPermissionSetDetailsClient) ListCustomerManagedPolicyReferencesInPermissionSet(_ context.Context, _ *ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetOutput, error) {
+	return &ssoadmin.ListCustomerManagedPolicyReferencesInPermissionSetOutput{CustomerManagedPolicyReferences: m.references}, nil
+}
+
+func (m *mockSSOPermissionSetDetailsClient) ListTagsForResource(_ context.Context, _ *ssoadmin.ListTagsForResourceInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.ListTagsForResourceOutput, error) {
+	return &ssoadmin.ListTagsForResourceOutput{Tags: m.tags}, nil
+}
+
+func (m *mockSSOPermissionSetDetailsClient) GetPermissionsBoundaryForPermissionSet(_ context.Context, _ *ssoadmin.GetPermissionsBoundaryForPermissionSetInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.GetPermissionsBoundaryForPermissionSetOutput, error) {
+	if m.boundary == nil {
+		return nil, &ssotypes.ResourceNotFoundException{Message: aws.String("no permissions boundary")}
+	}
+	return &ssoadmin.GetPermissionsBoundaryForPermissionSetOutput{PermissionsBoundary: m.boundary}, nil
+}
+
+// mockMissingPoliciesIAMClient returns NoSuchEntity for the missing policy ARNs.
+type mockMissingPoliciesIAMClient struct {
+	*mockIAMClient
+	missing []string
+}
+
+func (m *mockMissingPoliciesIAMClient) GetPolicy(ctx context.Context, input *iam.GetPolicyInput, optFns ...func(*iam.Options)) (*iam.GetPolicyOutput, error) {
+	for _, arn := range m.missing {
+		if arn == aws.ToString(input.PolicyArn) {
+			return nil, &iamtypes.NoSuchEntityException{Message: aws.String("policy not found")}
+		}
+	}
+	return m.mockIAMClient.GetPolicy(ctx, input, optFns...)
+}
+
+func testDetailedPermissionSet() SSOPermissionSet {
+	return SSOPermissionSet{
+		Name:            "Deploy",
+		Arn:             "arn:aws:sso:::permissionSet/ssoins-1/ps-1",
+		Instance:        &SSOInstance{Arn: "arn:aws:sso:::instance/ssoins-1"},
+		InlinePolicy:    `{"Statement":[{"Effect":"Deny","Action":"iam:*","Resource":"*"}]}`,
+		ManagedPolicies: []SSOPolicy{{Name: "ReadOnlyAccess", Arn: "arn:aws:iam::aws:policy/ReadOnlyAccess"}},
+	}
+}
+
+func TestSSOPermissionSet_AddDetails(t *testing.T) {
+	permissionset := testDetailedPermissionSet()
+	mock := &mockSSOPermissionSetDetailsClient{
+		references: []ssotypes.CustomerManagedPolicyReference{{Name: aws.String("DeployPolicy"), Path: aws.String("/deploy/")}},
+		boundary:   &ssotypes.PermissionsBoundary{CustomerManagedPolicyReference: &ssotypes.CustomerManagedPolicyReference{Name: aws.String("Boundary")}},
+		tags:       []ssotypes.Tag{{Key: aws.String("Team"), Value: aws.String("Platform")}},
+	}
+	require.NoError(t, permissionset.AddDetails(mock))
+	assert.Equal(t, []SSOCustomerManagedPolicy{{Name: "DeployPolicy", Path: "/deploy/"}}, permissionset.CustomerManagedPolicies)
+	require.NotNil(t, permissionset.PermissionsBoundary)
+	assert.Equal(t, "/Boundary", permissionset.PermissionsBoundary.Name())
+	assert.Equal(t, map[string]string{"Team": "Platform"}, permissionset.Tags)
+
+	mock.boundary = nil
+	require.NoError(t, permissionset.AddDetails(mock))
+	assert.Nil(t, permissionset.PermissionsBoundary)
+}
+
+func TestSSOCustomerManagedPolicy_ArnInAccount(t *testing.T) {
+	assert.Equal(t, "arn:aws:iam::111111111111:policy/deploy/DeployPolicy", SSOCustomerManagedPolicy{Name: "DeployPolicy", Path: "/deploy/"}.ArnInAccount("aws", "111111111111"))
+	assert.Equal(t, "arn:aws-cn:iam::111111111111:policy/DeployPolicy", SSOCustomerManagedPolicy{Name: "DeployPolicy"}.ArnInAccount("aws-cn", "111111111111"))
+}
+
+func TestSSOPermissionSet_AddPolicyDocuments(t *testing.T) {
+	permissionset := testDetailedPermissionSet()
+	original := permissionset.ManagedPolicies
+	permissionset.CustomerManagedPolicies = []SSOCustomerManagedPolicy{{Name: "DeployPolicy"}, {Name: "Missing"}}
+	permissionset.PermissionsBoundary = &SSOPermissionsBoundary{ManagedPolicyArn: "arn:aws:iam::aws:policy/PowerUserAccess"}
+	svc := &mockMissingPoliciesIAMClient{mockIAMClient: &mockIAMClient{}, missing: []string{"arn:aws:iam::111111111111:policy/Missing"}}
+
+	require.NoError(t, permissionset.AddPolicyDocuments("111111111111", svc))
+	assert.NotEmpty(t, permissionset.ManagedPolicies[0].Policy)
+	assert.Empty(t, original[0].Policy, "the managed policies of the instance aren't changed")
+	assert.NotEmpty(t, permissionset.CustomerManagedPolicies[0].Policy)
+	assert.Empty(t, permissionset.CustomerManagedPolicies[1].Policy)
+	assert.NotEmpty(t, permissionset.PermissionsBoundary.Policy)
+
+	principal, err := GetPermissionSetPolicies(permissionset, svc)
+	require.NoError(t, err)
+	names := make([]string, 0, len(principal.Policies))
+	for _, policy := range principal.Policies {
+		names = append(names, policy.Name)
+	}
+	// The missing customer managed policy isn't included
+	assert.Equal(t, []string{"Deploy", "ReadOnlyAccess", "DeployPolicy"}, names)
+	require.NotNil(t, principal.PermissionsBoundary)
+	assert.Equal(t, IAMPolicyTypePermissionsBoundary, principal.PermissionsBoundary.Type)
+}
+
+// mockSSOPermissionSetPoliciesClient implements SSOPermissionSetPoliciesAPI for testing.
+type mockSSOPermissionSetPoliciesClient struct {
+	*mockSSOAdminClient
+	*mockSSOPermissionSetDetailsClient
+}
+
+func TestGetComparablePrincipalPolicies_PermissionSetDetails(t *testing.T) {
+	ssoSvc := mockSSOPermissionSetPoliciesClient{
+		mockSSOAdminClient: newBasicMock(),
+		mockSSOPermissionSetDetailsClient: &mockSSOPermissionSetDetailsClient{
+			references: []ssotypes.CustomerManagedPolicyReference{{Name: aws.String("DeployPolicy")}},
+		},
+	}
+	iamSvc := &mockMissingPoliciesIAMClient{mockIAMClient: &mockIAMClient{}}
+
+	principal, err := GetComparablePrincipalPolicies("permission-set/TestPS", "111111111111", iamSvc, ssoSvc)
+	require.NoError(t, err)
+	require.Len(t, principal.Policies, 1)
+	assert.Equal(t, "DeployPolicy", principal.Policies[0].Name)
+
+	iamSvc.missing = []string{"arn:aws:iam::111111111111:policy/DeployPolicy"}
+	_, err = GetComparablePrincipalPolicies("permission-set/TestPS", "111111111111", iamSvc, ssoSvc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DeployPolicy")
+}
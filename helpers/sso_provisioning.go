@@ -0,0 +1,142 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+)
+
+// SSOProvisioningAPI defines the SSO Admin calls used to check and trigger
+// the provisioning of permission sets
+type SSOProvisioningAPI interface {
+	ssoadmin.ListAccountsForProvisionedPermissionSetAPIClient
+	ssoadmin.ListPermissionSetProvisioningStatusAPIClient
+	DescribePermissionSetProvisioningStatus(ctx context.Context, params *ssoadmin.DescribePermissionSetProvisioningStatusInput, optFns ...func(*ssoadmin.Options)) (*ssoadmin.DescribePermissionSetProvisioningStatusOutput, error)
+	ProvisionPermissionSet(ctx context.Context, params *ssoadmin.ProvisionPermissionSetInput, optFns ...func(*ssoadmin.Options)) (*ssoadmin.ProvisionPermissionSetOutput, error)
+}
+
+// SSOProvisioningStatus is the provisioning state of a permission set, where
+// outdated accounts still have a version from before its last update
+type SSOProvisioningStatus struct {
+	PermissionSet       SSOPermissionSet
+	ProvisionedAccounts []string
+	OutdatedAccounts    []string
+}
+
+// IsOutdated returns whether any account has an outdated version of the
+// permission set
+func (status SSOProvisioningStatus) IsOutdated() bool {
+	return len(status.OutdatedAccounts) > 0
+}
+
+// SSOProvisioningOperation is a request to provision a permission set
+type SSOProvisioningOperation struct {
+	RequestID        string
+	PermissionSetArn string
+	AccountID        string
+	Status           string
+	FailureReason    string
+	CreatedDate      time.Time
+}
+
+// GetProvisioningStatuses returns for every permission set in the instance
+// the accounts it's provisioned to and the ones that have an outdated version
+func (instance *SSOInstance) GetProvisioningStatuses(svc SSOProvisioningAPI) ([]SSOProvisioningStatus, error) {
+	result := make([]SSOProvisioningStatus, 0, len(instance.PermissionSets))
+	for _, permissionset := range instance.PermissionSets {
+		status := SSOProvisioningStatus{PermissionSet: permissionset}
+		var err error
+		status.ProvisionedAccounts, err = instance.getProvisionedAccounts(permissionset, "", svc)
+		if err != nil {
+			return nil, err
+		}
+		status.OutdatedAccounts, err = instance.getProvisionedAccounts(permissionset, ssotypes.ProvisioningStatusLatestPermissionSetNotProvisioned, svc)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+func (instance *SSOInstance) getProvisionedAccounts(permissionset SSOPermissionSet, status ssotypes.ProvisioningStatus, svc SSOProvisioningAPI) ([]string, error) {
+	var result []string
+	paginator := ssoadmin.NewListAccountsForProvisionedPermissionSetPaginator(svc, &ssoadmin.ListAccountsForProvisionedPermissionSetInput{
+		InstanceArn:        aws.String(instance.Arn),
+		PermissionSetArn:   aws.String(permissionset.Arn),
+		ProvisioningStatus: status,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts for permission set %s: %w", permissionset.Arn, err)
+		}
+		result = append(result, page.AccountIds...)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetFailedProvisioningOperations returns the permission set provisioning
+// requests that failed, newest first
+func (instance *SSOInstance) GetFailedProvisioningOperations(svc SSOProvisioningAPI) ([]SSOProvisioningOperation, error) {
+	var result []SSOProvisioningOperation
+	paginator := ssoadmin.NewListPermissionSetProvisioningStatusPaginator(svc, &ssoadmin.ListPermissionSetProvisioningStatusInput{
+		InstanceArn: aws.String(instance.Arn),
+		Filter:      &ssotypes.OperationStatusFilter{Status: ssotypes.StatusValuesFailed},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list permission set provisioning statuses: %w", err)
+		}
+		for _, metadata := range page.PermissionSetsProvisioningStatus {
+			details, err := svc.DescribePermissionSetProvisioningStatus(context.TODO(), &ssoadmin.DescribePermissionSetProvisioningStatusInput{
+				InstanceArn:                     aws.String(instance.Arn),
+				ProvisionPermissionSetRequestId: metadata.RequestId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to describe provisioning request %s: %w", aws.ToString(metadata.RequestId), err)
+			}
+			result = append(result, provisioningOperation(details.PermissionSetProvisioningStatus))
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedDate.After(result[j].CreatedDate)
+	})
+	return result, nil
+}
+
+// ReprovisionPermissionSet starts provisioning the latest version of the
+// permission set to the account
+func (instance *SSOInstance) ReprovisionPermissionSet(permissionset SSOPermissionSet, accountID string, svc SSOProvisioningAPI) (SSOProvisioningOperation, error) {
+	resp, err := svc.ProvisionPermissionSet(context.TODO(), &ssoadmin.ProvisionPermissionSetInput{
+		InstanceArn:      aws.String(instance.Arn),
+		PermissionSetArn: aws.String(permissionset.Arn),
+		TargetType:       ssotypes.ProvisionTargetTypeAwsAccount,
+		TargetId:         aws.String(accountID),
+	})
+	if err != nil {
+		return SSOProvisioningOperation{}, fmt.Errorf("failed to provision permission set %s to account %s: %w", permissionset.Name, accountID, err)
+	}
+	return provisioningOperation(resp.PermissionSetProvisioningStatus), nil
+}
+
+func provisioningOperation(status *ssotypes.PermissionSetProvisioningStatus) SSOProvisioningOperation {
+	if status == nil {
+		return SSOProvisioningOperation{}
+	}
+	return SSOProvisioningOperation{
+		RequestID:        aws.ToString(status.RequestId),
+		PermissionSetArn: aws.ToString(status.PermissionSetArn),
+		AccountID:        aws.ToString(status.AccountId),
+		Status:           string(status.Status),
+		FailureReason:    aws.ToString(status.FailureReason),
+		CreatedDate:      aws.ToTime(status.CreatedDate),
+	}
+}
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssoadmin"
+	ssotypes "github.com/aws/aws-sdk-go-v2/service/ssoadmin/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSSOProvisioningClient implements SSOProvisioningAPI for testing.
+type mockSSOProvisioningClient struct {
+	provisioned map[string][]string
+	outdated    map[string][]string
+	failed      []ssotypes.PermissionSetProvisioningStatus
+	provisions  []ssoadmin.ProvisionPermissionSetInput
+}
+
+func (m *mockSSOProvisioningClient) ListAccountsForProvisionedPermissionSet(_ context.Context, params *ssoadmin.ListAccountsForProvisionedPermissionSetInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.ListAccountsForProvisionedPermissionSetOutput, error) {
+	accounts := m.provisioned[aws.ToString(params.PermissionSetArn)]
+	if params.ProvisioningStatus == ssotypes.ProvisioningStatusLatestPermissionSetNotProvisioned {
+		accounts = m.outdated[aws.ToString(params.PermissionSetArn)]
+	}
+	return &ssoadmin.ListAccountsForProvisionedPermissionSetOutput{AccountIds: accounts}, nil
+}
+
+func (m *mockSSOProvisioningClient) ListPermissionSetProvisioningStatus(_ context.Context, params *ssoadmin.ListPermissionSetProvisioningStatusInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.ListPermissionSetProvisioningStatusOutput, error) {
+	output := &ssoadmin.ListPermissionSetProvisioningStatusOutput{}
+	for _, status := range m.failed {
+		if params.Filter != nil && params.Filter.Status != status.Status {
+			continue
+		}
+		output.PermissionSetsProvisioningStatus = append(output.PermissionSetsProvisioningStatus, ssotypes.PermissionSetProvisioningStatusMetadata{RequestId: status.RequestId, Status: status.Status})
+	}
+	return output, nil
+}
+
+func (m *mockSSOProvisioningClient) DescribePermissionSetProvisioningStatus(_ context.Context, params *ssoadmin.DescribePermissionSetProvisioningStatusInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.DescribePermissionSetProvisioningStatusOutput, error) {
+	for _, status := range m.failed {
+		if aws.ToString(status.RequestId) == aws.ToString(params.ProvisionPermissionSetRequestId) {
+			return &ssoadmin.DescribePermissionSetProvisioningStatusOutput{PermissionSetProvisioningStatus: &status}, nil
+		}
+	}
+	return &ssoadmin.DescribePermissionSetProvisioningStatusOutput{}, nil
+}
+
+func (m *mockSSOProvisioningClient) ProvisionPermissionSet(_ context.Context, params *ssoadmin.ProvisionPermissionSetInput, _ ...func(*ssoadmin.Options)) (*ssoadmin.ProvisionPermissionSetOutput, error) {
+	m.provisions = append(m.provisions, *params)
+	return &ssoadmin.ProvisionPermissionSetOutput{PermissionSetProvisioningStatus: &ssotypes.PermissionSetProvisioningStatus{
+		RequestId:        aws.String("req-new"),
+		AccountId:        params.TargetId,
+		PermissionSetArn: params.PermissionSetArn,
+		Status:           ssotypes.StatusValuesInProgress,
+	}}, nil
+}
+
+func TestSSOInstance_GetProvisioningStatuses(t *testing.T) {
+	instance := SSOInstance{Arn: "arn:instance", PermissionSets: []SSOPermissionSet{
+		{Name: "Admin", Arn: "arn:ps-admin"},
+		{Name: "ReadOnly", Arn: "arn:ps-readonly"},
+	}}
+	mock := &mockSSOProvisioningClient{
+		provisioned: map[string][]string{"arn:ps-admin": {"222222222222", "111111111111"}, "arn:ps-readonly": {"111111111111"}},
+		outdated:    map[string][]string{"arn:ps-admin": {"222222222222"}},
+	}
+	statuses, err := instance.GetProvisioningStatuses(mock)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.Equal(t, []string{"111111111111", "222222222222"}, statuses[0].ProvisionedAccounts)
+	assert.Equal(t, []string{"222222222222"}, statuses[0].OutdatedAccounts)
+	assert.True(t, statuses[0].IsOutdated())
+	assert.False(t, statuses[1].IsOutdated())
+}
+
+func TestSSOInstance_GetFailedProvisioningOperations(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	mock := &mockSSOProvisioningClient{failed: []ssotypes.PermissionSetProvisioningStatus{
+		{RequestId: aws.String("req-1"), Status: ssotypes.StatusValuesFailed, AccountId: aws.String("111111111111"), PermissionSetArn: aws.String("arn:ps-admin"), FailureReason: aws.String("Policy too large"), CreatedDate: aws.Time(older)},
+		{RequestId: aws.String("req-2"), Status: ssotypes.StatusValuesSucceeded},
+		{RequestId: aws.String("req-3"), Status: ssotypes.StatusValuesFailed, AccountId: aws.String("222222222222"), CreatedDate: aws.Time(newer)},
+	}}
+	instance := SSOInstance{Arn: "arn:instance"}
+	operations, err := instance.GetFailedProvisioningOperations(mock)
+	require.NoError(t, err)
+	require.Len(t, operations, 2)
+	assert.Equal(t, "req-3", operations[0].RequestID)
+	assert.Equal(t, SSOProvisioningOperation{RequestID: "req-1", PermissionSetArn: "arn:ps-admin", AccountID: "111111111111", Status: "FAILED", FailureReason: "Policy too large", CreatedDate: older}, operations[1])
+}
+
+func TestSSOInstance_ReprovisionPermissionSet(t *testing.T) {
+	instance := SSOInstance{Arn: "arn:instance"}
+	mock := &mockSSOProvisioningClient{}
+	operation, err := instance.ReprovisionPermissionSet(SSOPermissionSet{Name: "Admin", Arn: "arn:ps-admin"}, "222222222222", mock)
+	require.NoError(t, err)
+	assert.Equal(t, "req-new", operation.RequestID)
+	assert.Equal(t, "IN_PROGRESS", operation.Status)
+	require.Len(t, mock.provisions, 1)
+	assert.Equal(t, ssotypes.ProvisionTargetTypeAwsAccount, mock.provisions[0].TargetType)
+	assert.Equal(t, "222222222222", aws.ToString(mock.provisions[0].TargetId))
+	assert.Equal(t, "arn:instance", aws.ToString(mock.provisions[0].InstanceArn))
+}
@@ -0,0 +1,262 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+	ramtypes "github.com/aws/aws-sdk-go-v2/service/ram/types"
+	"github.com/aws/smithy-go"
+)
+
+// Issues found when analysing Transit Gateway routes for a return path
+const (
+	TransitGatewayDanglingOneWay             = "One-way route"
+	TransitGatewayDanglingMissingAssociation = "Missing association"
+	TransitGatewayDanglingMissingPropagation = "Missing propagation"
+)
+
+// TransitGatewayDanglingRoute is a route from a source attachment to a target
+// attachment for which the target has no route back to the source
+type TransitGatewayDanglingRoute struct {
+	TransitGateway   TransitGateway
+	Source           TransitGatewayAttachment
+	RouteTable       TransitGatewayRouteTable
+	Destinations     []string
+	Target           TransitGatewayAttachment
+	TargetRouteTable string
+	Issue            string
+	Fix              string
+}
+
+// FindDanglingTransitGatewayRoutes finds routes for which there is no return
+// path. For every attachment associated with a route table, each of the
+// attachments it routes to needs to be associated with a route table that
+// routes back to it. The analysis is done on attachment IDs, so attachments
+// owned by other accounts are treated the same as local ones. Findings are
+// classified as:
+//   - Missing association: the target isn't associated with any route table
+//   - Missing propagation: the source doesn't propagate into the target's route table
+//   - One-way route: the source can't propagate (peering) or does propagate,
+//     but the target's route table still has no route back to it
+func FindDanglingTransitGatewayRoutes(gateways []TransitGateway) []TransitGatewayDanglingRoute {
+	var result []TransitGatewayDanglingRoute
+	for _, gateway := range gateways {
+		associations := make(map[string]string)
+		for _, routetableID := range gateway.RouteTableIDs() {
+			for _, attachment := range gateway.RouteTables[routetableID].SourceAttachments {
+				associations[attachment.ID] = routetableID
+			}
+		}
+		for _, routetableID := range gateway.RouteTableIDs() {
+			routetable := gateway.RouteTables[routetableID]
+			for _, source := range routetable.SourceAttachments {
+				result = append(result, danglingRoutesForSource(gateway, routetable, source, associations)...)
+			}
+		}
+	}
+	return result
+}
+
+func danglingRoutesForSource(gateway TransitGateway, routetable TransitGatewayRouteTable, source TransitGatewayAttachment, associations map[string]string) []TransitGatewayDanglingRoute {
+	var result []TransitGatewayDanglingRoute
+	findings := make(map[string]int)
+	for _, route := range routetable.Routes {
+		target := route.Attachment
+		if route.State == "blackhole" || target.ID == "" || target.ID == source.ID {
+			continue
+		}
+		if index, ok := findings[target.ID]; ok {
+			result[index].Destinations = append(result[index].Destinations, route.CIDR)
+			continue
+		}
+		finding := TransitGatewayDanglingRoute{
+			TransitGateway: gateway,
+			Source:         source,
+			RouteTable:     routetable,
+			Destinations:   []string{route.CIDR},
+			Target:         target,
+		}
+		targetRouteTableID, associated := associations[target.ID]
+		targetRouteTable := gateway.RouteTables[targetRouteTableID]
+		switch {
+		case !associated:
+			finding.Issue = TransitGatewayDanglingMissingAssociation
+			finding.Fix = fmt.Sprintf("Associate %s with a route table that routes back to %s", target.ID, source.ID)
+		case len(targetRouteTable.RoutesToAttachment(source.ID)) > 0:
+			continue
+		case source.ResourceType != "peering" && !attachmentInList(source.ID, targetRouteTable.DestinationAttachments):
+			finding.TargetRouteTable = targetRouteTableID
+			finding.Issue = TransitGatewayDanglingMissingPropagation
+			finding.Fix = fmt.Sprintf("Enable propagation of %s to %s", source.ID, targetRouteTableID)
+		default:
+			finding.TargetRouteTable = targetRouteTableID
+			finding.Issue = TransitGatewayDanglingOneWay
+			finding.Fix = fmt.Sprintf("Add a static route for the CIDRs of %s to %s", source.ID, targetRouteTableID)
+		}
+		findings[target.ID] = len(result)
+		result = append(result, finding)
+	}
+	return result
+}
+
+func attachmentInList(attachmentID string, attachments []TransitGatewayAttachment) bool {
+	for _, attachment := range attachments {
+		if attachment.ID == attachmentID {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitGatewayAttachmentOwnership holds the owners of a Transit Gateway attachment
+type TransitGatewayAttachmentOwnership struct {
+	ID                    string
+	TransitGatewayID      string
+	TransitGatewayOwnerID string
+	ResourceType          string
+	ResourceID            string
+	ResourceOwnerID       string
+	State                 string
+}
+
+// IsCrossAccount returns whether the attached resource is owned by a different account than the Transit Gateway
+func (ownership TransitGatewayAttachmentOwnership) IsCrossAccount() bool {
+	return ownership.ResourceOwnerID != "" && ownership.ResourceOwnerID != ownership.TransitGatewayOwnerID
+}
+
+// GetTransitGatewayAttachmentOwnership returns the ownership details of all Transit Gateway attachments, keyed by attachment ID
+func GetTransitGatewayAttachmentOwnership(svc *ec2.Client) map[string]TransitGatewayAttachmentOwnership {
+	return getTransitGatewayAttachmentOwnership(svc)
+}
+
+// getTransitGatewayAttachmentOwnership implements
+// GetTransitGatewayAttachmentOwnership against the narrow
+// DescribeTransitGatewayAttachmentsAPIClient interface. It walks
+// NewDescribeTransitGatewayAttachmentsPaginator.
+func getTransitGatewayAttachmentOwnership(svc ec2.DescribeTransitGatewayAttachmentsAPIClient) map[string]TransitGatewayAttachmentOwnership {
+	result := make(map[string]TransitGatewayAttachmentOwnership)
+	paginator := ec2.NewDescribeTransitGatewayAttachmentsPaginator(svc, &ec2.DescribeTransitGatewayAttachmentsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, attachment := range page.TransitGatewayAttachments {
+			ownership := TransitGatewayAttachmentOwnership{
+				ID:                    aws.ToString(attachment.TransitGatewayAttachmentId),
+				TransitGatewayID:      aws.ToString(attachment.TransitGatewayId),
+				TransitGatewayOwnerID: aws.ToString(attachment.TransitGatewayOwnerId),
+				ResourceType:          string(attachment.ResourceType),
+				ResourceID:            aws.ToString(attachment.ResourceId),
+				ResourceOwnerID:       aws.ToString(attachment.ResourceOwnerId),
+				State:                 string(attachment.State),
+			}
+			result[ownership.ID] = ownership
+		}
+	}
+	return result
+}
+
+// TransitGatewayShare is a RAM resource share that shares Transit Gateways
+// with other principals
+type TransitGatewayShare struct {
+	Arn        string
+	Name       string
+	Status     string
+	Principals []string
+}
+
+// SharesWith returns whether the share gives the account access. Shares with
+// an organization or organizational unit are assumed to include the account,
+// as RAM doesn't expose which accounts are part of them.
+func (share TransitGatewayShare) SharesWith(accountID string) bool {
+	for _, principal := range share.Principals {
+		if principal == accountID || strings.Contains(principal, ":organization/") || strings.Contains(principal, ":ou/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RAMAPI is the RAM API surface needed to look up Transit Gateway shares
+type RAMAPI interface {
+	ram.ListPrincipalsAPIClient
+	ram.GetResourceSharesAPIClient
+}
+
+// GetTransitGatewayShares returns the RAM resource shares owned by the
+// account that contain Transit Gateways, together with their principals
+func GetTransitGatewayShares(svc RAMAPI) ([]TransitGatewayShare, error) {
+	principals := make(map[string][]string)
+	var shareArns []string
+	paginator := ram.NewListPrincipalsPaginator(svc, &ram.ListPrincipalsInput{
+		ResourceOwner: ramtypes.ResourceOwnerSelf,
+		ResourceType:  aws.String("ec2:TransitGateway"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to list principals of Transit Gateway shares: %w", err)
+		}
+		for _, principal := range page.Principals {
+			shareArn := aws.ToString(principal.ResourceShareArn)
+			if _, ok := principals[shareArn]; !ok {
+				shareArns = append(shareArns, shareArn)
+			}
+			principals[shareArn] = append(principals[shareArn], aws.ToString(principal.Id))
+		}
+	}
+	if len(shareArns) == 0 {
+		return nil, nil
+	}
+	var result []TransitGatewayShare
+	sharePaginator := ram.NewGetResourceSharesPaginator(svc, &ram.GetResourceSharesInput{
+		ResourceOwner:     ramtypes.ResourceOwnerSelf,
+		ResourceShareArns: shareArns,
+	})
+	for sharePaginator.HasMorePages() {
+		page, err := sharePaginator.NextPage(context.TODO())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Transit Gateway shares: %w", err)
+		}
+		for _, share := range page.ResourceShares {
+			arn := aws.ToString(share.ResourceShareArn)
+			result = append(result, TransitGatewayShare{
+				Arn:        arn,
+				Name:       aws.ToString(share.Name),
+				Status:     string(share.Status),
+				Principals: principals[arn],
+			})
+		}
+	}
+	return result, nil
+}
+
+// IsAccessDeniedError returns whether the error is caused by missing permissions
+func IsAccessDeniedError(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation":
+		return true
+	}
+	return false
+}
+
+// SharesForAccount returns the names of the shares that give the account access
+func SharesForAccount(shares []TransitGatewayShare, accountID string) []string {
+	var result []string
+	for _, share := range shares {
+		if share.SharesWith(accountID) {
+			result = append(result, share.Name)
+		}
+	}
+	return result
+}
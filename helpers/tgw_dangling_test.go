@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ram"
+	ramtypes "github.com/aws/aws-sdk-go-v2/service/ram/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestFindDanglingTransitGatewayRoutes(t *testing.T) {
+	vpcA := TransitGatewayAttachment{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}
+	vpcB := TransitGatewayAttachment{ID: "tgw-attach-b", ResourceType: "vpc", ResourceID: "vpc-b"}
+	vpcC := TransitGatewayAttachment{ID: "tgw-attach-c", ResourceType: "vpc", ResourceID: "vpc-c"}
+	vpcD := TransitGatewayAttachment{ID: "tgw-attach-d", ResourceType: "vpc", ResourceID: "vpc-d"}
+	peer := TransitGatewayAttachment{ID: "tgw-attach-peer", ResourceType: "peering", ResourceID: "tgw-remote"}
+	gateways := []TransitGateway{
+		{
+			ID: "tgw-1",
+			RouteTables: map[string]TransitGatewayRouteTable{
+				"tgw-rtb-a": {
+					ID:                     "tgw-rtb-a",
+					SourceAttachments:      []TransitGatewayAttachment{vpcA, peer},
+					DestinationAttachments: []TransitGatewayAttachment{vpcB},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "10.1.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcB},
+						{CIDR: "10.2.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcC},
+						{CIDR: "10.3.0.0/16", State: "active", RouteType: "static", Attachment: vpcD},
+						{CIDR: "10.4.0.0/16", State: "active", RouteType: "static", Attachment: vpcD},
+						{CIDR: "10.9.0.0/16", State: "blackhole", RouteType: "static"},
+					},
+				},
+				"tgw-rtb-b": {
+					ID:                     "tgw-rtb-b",
+					SourceAttachments:      []TransitGatewayAttachment{vpcB},
+					DestinationAttachments: []TransitGatewayAttachment{vpcA},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "10.0.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcA},
+					},
+				},
+				"tgw-rtb-c": {
+					ID:                "tgw-rtb-c",
+					SourceAttachments: []TransitGatewayAttachment{vpcC},
+				},
+			},
+		},
+	}
+
+	got := FindDanglingTransitGatewayRoutes(gateways)
+
+	type summary struct {
+		Source, Target, TargetRouteTable, Issue string
+		Destinations                            []string
+	}
+	var summaries []summary
+	for _, finding := range got {
+		summaries = append(summaries, summary{finding.Source.ID, finding.Target.ID, finding.TargetRouteTable, finding.Issue, finding.Destinations})
+	}
+	expected := []summary{
+		{"tgw-attach-a", "tgw-attach-c", "tgw-rtb-c", TransitGatewayDanglingMissingPropagation, []string{"10.2.0.0/16"}},
+		{"tgw-attach-a", "tgw-attach-d", "", TransitGatewayDanglingMissingAssociation, []string{"10.3.0.0/16", "10.4.0.0/16"}},
+		{"tgw-attach-peer", "tgw-attach-b", "tgw-rtb-b", TransitGatewayDanglingOneWay, []string{"10.1.0.0/16"}},
+		{"tgw-attach-peer", "tgw-attach-c", "tgw-rtb-c", TransitGatewayDanglingOneWay, []string{"10.2.0.0/16"}},
+		{"tgw-attach-peer", "tgw-attach-d", "", TransitGatewayDanglingMissingAssociation, []string{"10.3.0.0/16", "10.4.0.0/16"}},
+	}
+	if !reflect.DeepEqual(summaries, expected) {
+		t.Errorf("FindDanglingTransitGatewayRoutes() =\n%+v\nwant\n%+v", summaries, expected)
+	}
+	if got[0].Fix != "Enable propagation of tgw-attach-a to tgw-rtb-c" {
+		t.Errorf("unexpected fix %q", got[0].Fix)
+	}
+}
+
+type mockTGWAttachmentsClient struct {
+	attachments []types.TransitGatewayAttachment
+	pageSize    int
+	calls       int
+}
+
+func (m *mockTGWAttachmentsClient) DescribeTransitGatewayAttachments(_ context.Context, input *ec2.DescribeTransitGatewayAttachmentsInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayAttachmentsOutput, error) {
+	m.calls++
+	start := 0
+	if input.NextToken != nil {
+		if _, err := fmt.Sscanf(*input.NextToken, "%d", &start); err != nil {
+			return nil, err
+		}
+	}
+	end := min(start+m.pageSize, len(m.attachments))
+	out := &ec2.DescribeTransitGatewayAttachmentsOutput{TransitGatewayAttachments: m.attachments[start:end]}
+	if end < len(m.attachments) {
+		tok := fmt.Sprintf("%d", end)
+		out.NextToken = &tok
+	}
+	return out, nil
+}
+
+func TestGetTransitGatewayAttachmentOwnership(t *testing.T) {
+	mock := &mockTGWAttachmentsClient{
+		attachments: []types.TransitGatewayAttachment{
+			{TransitGatewayAttachmentId: aws.String("tgw-attach-a"), TransitGatewayOwnerId: aws.String("111111111111"), ResourceOwnerId: aws.String("111111111111"), ResourceId: aws.String("vpc-a"), ResourceType: types.TransitGatewayAttachmentResourceTypeVpc},
+			{TransitGatewayAttachmentId: aws.String("tgw-attach-b"), TransitGatewayOwnerId: aws.String("111111111111"), ResourceOwnerId: aws.String("222222222222"), ResourceId: aws.String("vpc-b"), ResourceType: types.TransitGatewayAttachmentResourceTypeVpc},
+			{TransitGatewayAttachmentId: aws.String("tgw-attach-c"), TransitGatewayOwnerId: aws.String("111111111111"), ResourceOwnerId: aws.String("111111111111"), ResourceId: aws.String("vpn-c"), ResourceType: types.TransitGatewayAttachmentResourceTypeVpn},
+		},
+		pageSize: 2,
+	}
+
+	result := getTransitGatewayAttachmentOwnership(mock)
+
+	if len(result) != 3 || mock.calls != 2 {
+		t.Fatalf("got %d attachments in %d calls, want 3 in 2", len(result), mock.calls)
+	}
+	if result["tgw-attach-a"].IsCrossAccount() || !result["tgw-attach-b"].IsCrossAccount() {
+		t.Error("IsCrossAccount() returned an unexpected result")
+	}
+	if result["tgw-attach-c"].ResourceType != "vpn" {
+		t.Errorf("ResourceType = %q, want vpn", result["tgw-attach-c"].ResourceType)
+	}
+}
+
+type mockRAMClient struct {
+	err        error
+	principals []ramtypes.Principal
+	shares     []ramtypes.ResourceShare
+	shareInput *ram.GetResourceSharesInput
+}
+
+func (m *mockRAMClient) ListPrincipals(_ context.Context, input *ram.ListPrincipalsInput, _ ...func(*ram.Options)) (*ram.ListPrincipalsOutput, error) {
+	if aws.ToString(input.ResourceType) != "ec2:TransitGateway" || input.ResourceOwner != ramtypes.ResourceOwnerSelf {
+		return nil, fmt.Errorf("unexpected input %+v", input)
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ram.ListPrincipalsOutput{Principals: m.principals}, nil
+}
+
+func (m *mockRAMClient) GetResourceShares(_ context.Context, input *ram.GetResourceSharesInput, _ ...func(*ram.Options)) (*ram.GetResourceSharesOutput, error) {
+	m.shareInput = input
+	return &ram.GetResourceSharesOutput{ResourceShares: m.shares}, nil
+}
+
+func TestGetTransitGatewayShares(t *testing.T) {
+	direct := "arn:aws:ram:ap-southeast-2:111111111111:resource-share/direct"
+	org := "arn:aws:ram:ap-southeast-2:111111111111:resource-share/org"
+	mock := &mockRAMClient{
+		principals: []ramtypes.Principal{
+			{Id: aws.String("222222222222"), ResourceShareArn: aws.String(direct)},
+			{Id: aws.String("333333333333"), ResourceShareArn: aws.String(direct)},
+			{Id: aws.String("arn:aws:organizations::111111111111:ou/o-abc/ou-abc-12345678"), ResourceShareArn: aws.String(org)},
+		},
+		shares: []ramtypes.ResourceShare{
+			{ResourceShareArn: aws.String(direct), Name: aws.String("tgw-partners"), Status: ramtypes.ResourceShareStatusActive},
+			{ResourceShareArn: aws.String(org), Name: aws.String("tgw-workloads"), Status: ramtypes.ResourceShareStatusActive},
+		},
+	}
+
+	shares, err := GetTransitGatewayShares(mock)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if !reflect.DeepEqual(mock.shareInput.ResourceShareArns, []string{direct, org}) {
+		t.Errorf("GetResourceShares called with %v", mock.shareInput.ResourceShareArns)
+	}
+	if len(shares) != 2 || !reflect.DeepEqual(shares[0].Principals, []string{"222222222222", "333333333333"}) {
+		t.Fatalf("unexpected shares %+v", shares)
+	}
+	if got := SharesForAccount(shares, "222222222222"); !reflect.DeepEqual(got, []string{"tgw-partners", "tgw-workloads"}) {
+		t.Errorf("SharesForAccount() = %v", got)
+	}
+	if got := SharesForAccount(shares, "444444444444"); !reflect.DeepEqual(got, []string{"tgw-workloads"}) {
+		t.Errorf("SharesForAccount() = %v, want only the OU share", got)
+	}
+	if shares, err := GetTransitGatewayShares(&mockRAMClient{}); shares != nil || err != nil {
+		t.Errorf("expected no shares when there are no principals, got %v, %v", shares, err)
+	}
+}
+
+func TestGetTransitGatewaySharesAccessDenied(t *testing.T) {
+	denied := &smithy.GenericAPIError{Code: "AccessDeniedException", Message: "not authorized to perform ram:ListPrincipals"}
+	shares, err := GetTransitGatewayShares(&mockRAMClient{err: denied})
+	if shares != nil || err == nil {
+		t.Fatalf("expected an error, got %v, %v", shares, err)
+	}
+	if !IsAccessDeniedError(err) {
+		t.Errorf("expected %v to be an access denied error", err)
+	}
+	if IsAccessDeniedError(&smithy.GenericAPIError{Code: "ThrottlingException"}) || IsAccessDeniedError(fmt.Errorf("network error")) {
+		t.Error("expected other errors not to be access denied errors")
+	}
+}
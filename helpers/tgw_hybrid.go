@@ -0,0 +1,269 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	dxtypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// VPNTunnel reflects the telemetry of a single VPN tunnel
+type VPNTunnel struct {
+	OutsideIP          string
+	Status             string
+	StatusMessage      string
+	AcceptedRouteCount int32
+	LastStatusChange   *time.Time
+}
+
+// IsUp returns whether the tunnel is up
+func (tunnel VPNTunnel) IsUp() bool {
+	return tunnel.Status == string(types.TelemetryStatusUp)
+}
+
+// VPNConnection reflects a Site-to-Site VPN connection and the health of its tunnels
+type VPNConnection struct {
+	ID                string
+	Name              string
+	State             string
+	CustomerGatewayID string
+	TransitGatewayID  string
+	StaticRoutesOnly  bool
+	Tunnels           []VPNTunnel
+}
+
+// RoutingType returns whether the VPN connection uses BGP or static routing
+func (vpn VPNConnection) RoutingType() string {
+	if vpn.StaticRoutesOnly {
+		return "Static"
+	}
+	return "BGP"
+}
+
+// BGPStatus returns what the tunnel telemetry shows about BGP for the tunnel.
+// The telemetry only has the status of the tunnel itself, not of the BGP
+// session, so the number of accepted routes is the best indication that BGP
+// is working.
+func (vpn VPNConnection) BGPStatus(tunnel VPNTunnel) string {
+	switch {
+	case vpn.StaticRoutesOnly:
+		return "n/a"
+	case !tunnel.IsUp():
+		return "down"
+	case tunnel.AcceptedRouteCount > 0:
+		return fmt.Sprintf("up (tunnel), %d routes accepted", tunnel.AcceptedRouteCount)
+	}
+	return "up (tunnel), no routes accepted"
+}
+
+// TunnelsUp returns the number of tunnels that are up
+func (vpn VPNConnection) TunnelsUp() int {
+	up := 0
+	for _, tunnel := range vpn.Tunnels {
+		if tunnel.IsUp() {
+			up++
+		}
+	}
+	return up
+}
+
+// AcceptedRouteCount returns the total number of routes accepted over all tunnels
+func (vpn VPNConnection) AcceptedRouteCount() int32 {
+	var count int32
+	for _, tunnel := range vpn.Tunnels {
+		count += tunnel.AcceptedRouteCount
+	}
+	return count
+}
+
+// Summary returns a short description of the health of the VPN connection
+func (vpn VPNConnection) Summary() string {
+	return fmt.Sprintf("%d/%d tunnels up, %s, %d accepted routes", vpn.TunnelsUp(), len(vpn.Tunnels), vpn.RoutingType(), vpn.AcceptedRouteCount())
+}
+
+// GetTransitGatewayVPNConnections returns the VPN connections that are attached to a Transit Gateway
+func GetTransitGatewayVPNConnections(svc *ec2.Client) ([]VPNConnection, error) {
+	return getTransitGatewayVPNConnections(svc)
+}
+
+// getTransitGatewayVPNConnections implements GetTransitGatewayVPNConnections
+// against the narrow DescribeVpnConnectionsAPIClient interface. The
+// DescribeVpnConnections API is not paginated, so a single call returns every
+// VPN connection in the region.
+func getTransitGatewayVPNConnections(svc ec2.DescribeVpnConnectionsAPIClient) ([]VPNConnection, error) {
+	resp, err := svc.DescribeVpnConnections(context.TODO(), &ec2.DescribeVpnConnectionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPN connections: %w", err)
+	}
+	var result []VPNConnection
+	for _, connection := range resp.VpnConnections {
+		if connection.TransitGatewayId == nil || connection.State == types.VpnStateDeleted {
+			continue
+		}
+		vpn := VPNConnection{
+			ID:                aws.ToString(connection.VpnConnectionId),
+			Name:              getNameFromTags(connection.Tags),
+			State:             string(connection.State),
+			CustomerGatewayID: aws.ToString(connection.CustomerGatewayId),
+			TransitGatewayID:  aws.ToString(connection.TransitGatewayId),
+		}
+		if connection.Options != nil {
+			vpn.StaticRoutesOnly = aws.ToBool(connection.Options.StaticRoutesOnly)
+		}
+		for _, telemetry := range connection.VgwTelemetry {
+			vpn.Tunnels = append(vpn.Tunnels, VPNTunnel{
+				OutsideIP:          aws.ToString(telemetry.OutsideIpAddress),
+				Status:             string(telemetry.Status),
+				StatusMessage:      aws.ToString(telemetry.StatusMessage),
+				AcceptedRouteCount: aws.ToInt32(telemetry.AcceptedRouteCount),
+				LastStatusChange:   telemetry.LastStatusChange,
+			})
+		}
+		result = append(result, vpn)
+	}
+	return result, nil
+}
+
+// DirectConnectGatewayAssociation reflects the association between a Direct
+// Connect gateway and a Transit Gateway
+type DirectConnectGatewayAssociation struct {
+	ID                     string
+	DirectConnectGatewayID string
+	GatewayName            string
+	GatewayState           string
+	AmazonSideASN          int64
+	OwnerAccount           string
+	TransitGatewayID       string
+	State                  string
+	StateChangeError       string
+	AllowedPrefixes        []string
+}
+
+// Summary returns a short description of the association
+func (association DirectConnectGatewayAssociation) Summary() string {
+	return fmt.Sprintf("%s, allowed prefixes: %s", association.State, strings.Join(association.AllowedPrefixes, ", "))
+}
+
+// DirectConnectAPI is the Direct Connect API surface used to collect the
+// Direct Connect gateway details for Transit Gateways
+type DirectConnectAPI interface {
+	DescribeDirectConnectGatewayAssociations(ctx context.Context, params *directconnect.DescribeDirectConnectGatewayAssociationsInput, optFns ...func(*directconnect.Options)) (*directconnect.DescribeDirectConnectGatewayAssociationsOutput, error)
+	DescribeDirectConnectGateways(ctx context.Context, params *directconnect.DescribeDirectConnectGatewaysInput, optFns ...func(*directconnect.Options)) (*directconnect.DescribeDirectConnectGatewaysOutput, error)
+}
+
+// GetDirectConnectGatewayAssociations returns the Direct Connect gateway
+// associations for the provided Transit Gateway. The Direct Connect APIs have
+// no paginators, so the NextToken is followed manually.
+func GetDirectConnectGatewayAssociations(tgwID string, svc DirectConnectAPI) ([]DirectConnectGatewayAssociation, error) {
+	var result []DirectConnectGatewayAssociation
+	gateways := make(map[string]dxtypes.DirectConnectGateway)
+	input := &directconnect.DescribeDirectConnectGatewayAssociationsInput{
+		AssociatedGatewayId: aws.String(tgwID),
+	}
+	for {
+		resp, err := svc.DescribeDirectConnectGatewayAssociations(context.TODO(), input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe Direct Connect gateway associations of %s: %w", tgwID, err)
+		}
+		for _, dxassociation := range resp.DirectConnectGatewayAssociations {
+			if dxassociation.AssociationState == dxtypes.DirectConnectGatewayAssociationStateDisassociated {
+				continue
+			}
+			association := DirectConnectGatewayAssociation{
+				ID:                     aws.ToString(dxassociation.AssociationId),
+				DirectConnectGatewayID: aws.ToString(dxassociation.DirectConnectGatewayId),
+				OwnerAccount:           aws.ToString(dxassociation.DirectConnectGatewayOwnerAccount),
+				TransitGatewayID:       tgwID,
+				State:                  string(dxassociation.AssociationState),
+				StateChangeError:       aws.ToString(dxassociation.StateChangeError),
+			}
+			for _, prefix := range dxassociation.AllowedPrefixesToDirectConnectGateway {
+				association.AllowedPrefixes = append(association.AllowedPrefixes, aws.ToString(prefix.Cidr))
+			}
+			gateway, ok := gateways[association.DirectConnectGatewayID]
+			if !ok {
+				gateway, err = getDirectConnectGateway(association.DirectConnectGatewayID, svc)
+				if err != nil {
+					return nil, err
+				}
+				gateways[association.DirectConnectGatewayID] = gateway
+			}
+			association.GatewayName = aws.ToString(gateway.DirectConnectGatewayName)
+			association.GatewayState = string(gateway.DirectConnectGatewayState)
+			association.AmazonSideASN = aws.ToInt64(gateway.AmazonSideAsn)
+			result = append(result, association)
+		}
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	return result, nil
+}
+
+func getDirectConnectGateway(gatewayID string, svc DirectConnectAPI) (dxtypes.DirectConnectGateway, error) {
+	resp, err := svc.DescribeDirectConnectGateways(context.TODO(), &directconnect.DescribeDirectConnectGatewaysInput{
+		DirectConnectGatewayId: aws.String(gatewayID),
+	})
+	if err != nil {
+		return dxtypes.DirectConnectGateway{}, fmt.Errorf("failed to describe Direct Connect gateway %s: %w", gatewayID, err)
+	}
+	if len(resp.DirectConnectGateways) == 0 {
+		return dxtypes.DirectConnectGateway{DirectConnectGatewayId: aws.String(gatewayID)}, nil
+	}
+	return resp.DirectConnectGateways[0], nil
+}
+
+// HybridConnectivity holds the VPN connections and Direct Connect gateway
+// associations of Transit Gateways
+type HybridConnectivity struct {
+	VPNConnections            []VPNConnection
+	DirectConnectAssociations []DirectConnectGatewayAssociation
+}
+
+// GetHybridConnectivity collects the VPN connections and Direct Connect
+// gateway associations for the provided Transit Gateways. If a lookup fails,
+// the connectivity collected up to that point is returned with the error, so
+// the VPN connections are still available when only the Direct Connect
+// lookup fails.
+func GetHybridConnectivity(gateways []TransitGateway, ec2svc *ec2.Client, dxsvc DirectConnectAPI) (HybridConnectivity, error) {
+	var result HybridConnectivity
+	var err error
+	result.VPNConnections, err = GetTransitGatewayVPNConnections(ec2svc)
+	if err != nil {
+		return result, err
+	}
+	for _, gateway := range gateways {
+		associations, err := GetDirectConnectGatewayAssociations(gateway.ID, dxsvc)
+		if err != nil {
+			return result, err
+		}
+		result.DirectConnectAssociations = append(result.DirectConnectAssociations, associations...)
+	}
+	return result, nil
+}
+
+// Summaries returns a short health summary for each hybrid resource of the
+// Transit Gateway, keyed by the resource ID used in its attachments (the VPN
+// connection ID or the Direct Connect gateway ID). A Direct Connect gateway
+// can be associated with multiple Transit Gateways, each with its own state.
+func (hybrid HybridConnectivity) Summaries(transitGatewayID string) map[string]string {
+	result := make(map[string]string)
+	for _, vpn := range hybrid.VPNConnections {
+		if vpn.TransitGatewayID == transitGatewayID {
+			result[vpn.ID] = vpn.Summary()
+		}
+	}
+	for _, association := range hybrid.DirectConnectAssociations {
+		if association.TransitGatewayID == transitGatewayID {
+			result[association.DirectConnectGatewayID] = association.Summary()
+		}
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	dxtypes "github.com/aws/aws-sdk-go-v2/service/directconnect/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/smithy-go"
+)
+
+type mockVPNConnectionsClient struct {
+	connections []types.VpnConnection
+	err         error
+}
+
+func (m *mockVPNConnectionsClient) DescribeVpnConnections(_ context.Context, _ *ec2.DescribeVpnConnectionsInput, _ ...func(*ec2.Options)) (*ec2.DescribeVpnConnectionsOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ec2.DescribeVpnConnectionsOutput{VpnConnections: m.connections}, nil
+}
+
+// mockDirectConnectClient paginates the associations with an offset NextToken
+type mockDirectConnectClient struct {
+	associations     []dxtypes.DirectConnectGatewayAssociation
+	gateways         map[string]dxtypes.DirectConnectGateway
+	pageSize         int
+	associationCalls int
+	gatewayCalls     int
+	gatewayErr       error
+}
+
+func (m *mockDirectConnectClient) DescribeDirectConnectGatewayAssociations(_ context.Context, input *directconnect.DescribeDirectConnectGatewayAssociationsInput, _ ...func(*directconnect.Options)) (*directconnect.DescribeDirectConnectGatewayAssociationsOutput, error) {
+	m.associationCalls++
+	start := 0
+	if input.NextToken != nil {
+		if _, err := fmt.Sscanf(*input.NextToken, "%d", &start); err != nil {
+			return nil, err
+		}
+	}
+	pageSize := m.pageSize
+	if pageSize == 0 {
+		pageSize = len(m.associations)
+	}
+	end := min(start+pageSize, len(m.associations))
+	out := &directconnect.DescribeDirectConnectGatewayAssociationsOutput{
+		DirectConnectGatewayAssociations: m.associations[start:end],
+	}
+	if end < len(m.associations) {
+		tok := fmt.Sprintf("%d", end)
+		out.NextToken = &tok
+	}
+	return out, nil
+}
+
+func (m *mockDirectConnectClient) DescribeDirectConnectGateways(_ context.Context, input *directconnect.DescribeDirectConnectGatewaysInput, _ ...func(*directconnect.Options)) (*directconnect.DescribeDirectConnectGatewaysOutput, error) {
+	m.gatewayCalls++
+	if m.gatewayErr != nil {
+		return nil, m.gatewayErr
+	}
+	out := &directconnect.DescribeDirectConnectGatewaysOutput{}
+	if gateway, ok := m.gateways[aws.ToString(input.DirectConnectGatewayId)]; ok {
+		out.DirectConnectGateways = []dxtypes.DirectConnectGateway{gateway}
+	}
+	return out, nil
+}
+
+func TestGetTransitGatewayVPNConnections(t *testing.T) {
+	mock := &mockVPNConnectionsClient{
+		connections: []types.VpnConnection{
+			{
+				VpnConnectionId:   aws.String("vpn-bgp"),
+				TransitGatewayId:  aws.String("tgw-1"),
+				CustomerGatewayId: aws.String("cgw-1"),
+				State:             types.VpnStateAvailable,
+				Tags:              []types.Tag{{Key: aws.String("Name"), Value: aws.String("datacenter")}},
+				Options:           &types.VpnConnectionOptions{StaticRoutesOnly: aws.Bool(false)},
+				VgwTelemetry: []types.VgwTelemetry{
+					{OutsideIpAddress: aws.String("203.0.113.1"), Status: types.TelemetryStatusUp, AcceptedRouteCount: aws.Int32(12), StatusMessage: aws.String("12 BGP ROUTES")},
+					{OutsideIpAddress: aws.String("203.0.113.2"), Status: types.TelemetryStatusDown, AcceptedRouteCount: aws.Int32(0)},
+				},
+			},
+			{
+				VpnConnectionId:  aws.String("vpn-static"),
+				TransitGatewayId: aws.String("tgw-1"),
+				State:            types.VpnStateAvailable,
+				Options:          &types.VpnConnectionOptions{StaticRoutesOnly: aws.Bool(true)},
+				VgwTelemetry: []types.VgwTelemetry{
+					{OutsideIpAddress: aws.String("203.0.113.3"), Status: types.TelemetryStatusUp},
+				},
+			},
+			{VpnConnectionId: aws.String("vpn-vgw"), VpnGatewayId: aws.String("vgw-1"), State: types.VpnStateAvailable},
+			{VpnConnectionId: aws.String("vpn-deleted"), TransitGatewayId: aws.String("tgw-1"), State: types.VpnStateDeleted},
+		},
+	}
+
+	result, err := getTransitGatewayVPNConnections(mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result) != 2 {
+		t.Fatalf("got %d VPN connections, want 2 (only non-deleted TGW VPNs)", len(result))
+	}
+	bgp := result[0]
+	if bgp.Name != "datacenter" || bgp.RoutingType() != "BGP" || bgp.TunnelsUp() != 1 || bgp.AcceptedRouteCount() != 12 {
+		t.Errorf("unexpected BGP VPN %+v", bgp)
+	}
+	if got := bgp.BGPStatus(bgp.Tunnels[0]); got != "up (tunnel), 12 routes accepted" {
+		t.Errorf("BGPStatus() = %q for an up tunnel with routes", got)
+	}
+	if got := bgp.BGPStatus(bgp.Tunnels[1]); got != "down" {
+		t.Errorf("BGPStatus() = %q for a down tunnel", got)
+	}
+	if got := bgp.BGPStatus(VPNTunnel{Status: string(types.TelemetryStatusUp)}); got != "up (tunnel), no routes accepted" {
+		t.Errorf("BGPStatus() = %q for an up tunnel without routes", got)
+	}
+	if got := bgp.Summary(); got != "1/2 tunnels up, BGP, 12 accepted routes" {
+		t.Errorf("Summary() = %q", got)
+	}
+	static := result[1]
+	if static.RoutingType() != "Static" || static.BGPStatus(static.Tunnels[0]) != "n/a" {
+		t.Errorf("unexpected static VPN %+v", static)
+	}
+}
+
+func TestGetDirectConnectGatewayAssociations(t *testing.T) {
+	mock := &mockDirectConnectClient{
+		associations: []dxtypes.DirectConnectGatewayAssociation{
+			{
+				AssociationId:          aws.String("assoc-1"),
+				DirectConnectGatewayId: aws.String("dxgw-1"),
+				AssociationState:       dxtypes.DirectConnectGatewayAssociationStateAssociated,
+				AllowedPrefixesToDirectConnectGateway: []dxtypes.RouteFilterPrefix{
+					{Cidr: aws.String("10.0.0.0/8")},
+					{Cidr: aws.String("172.16.0.0/12")},
+				},
+			},
+			{
+				AssociationId:          aws.String("assoc-2"),
+				DirectConnectGatewayId: aws.String("dxgw-1"),
+				AssociationState:       dxtypes.DirectConnectGatewayAssociationStateDisassociated,
+			},
+			{
+				AssociationId:          aws.String("assoc-3"),
+				DirectConnectGatewayId: aws.String("dxgw-2"),
+				AssociationState:       dxtypes.DirectConnectGatewayAssociationStateUpdating,
+			},
+		},
+		gateways: map[string]dxtypes.DirectConnectGateway{
+			"dxgw-1": {DirectConnectGatewayId: aws.String("dxgw-1"), DirectConnectGatewayName: aws.String("primary"), AmazonSideAsn: aws.Int64(64512), DirectConnectGatewayState: dxtypes.DirectConnectGatewayStateAvailable},
+		},
+		pageSize: 1,
+	}
+
+	result, err := GetDirectConnectGatewayAssociations("tgw-1", mock)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if mock.associationCalls != 3 {
+		t.Errorf("DescribeDirectConnectGatewayAssociations called %d times, want 3", mock.associationCalls)
+	}
+	if mock.gatewayCalls != 2 {
+		t.Errorf("DescribeDirectConnectGateways called %d times, want 2 (one per gateway)", mock.gatewayCalls)
+	}
+	if len(result) != 2 {
+		t.Fatalf("got %d associations, want 2 (disassociated ones are skipped)", len(result))
+	}
+	first := result[0]
+	if first.GatewayName != "primary" || first.AmazonSideASN != 64512 || first.TransitGatewayID != "tgw-1" {
+		t.Errorf("unexpected association %+v", first)
+	}
+	if !reflect.DeepEqual(first.AllowedPrefixes, []string{"10.0.0.0/8", "172.16.0.0/12"}) {
+		t.Errorf("AllowedPrefixes = %v", first.AllowedPrefixes)
+	}
+	if got := first.Summary(); got != "associated, allowed prefixes: 10.0.0.0/8, 172.16.0.0/12" {
+		t.Errorf("Summary() = %q", got)
+	}
+	if result[1].GatewayName != "" || result[1].DirectConnectGatewayID != "dxgw-2" {
+		t.Errorf("unexpected association for unknown gateway %+v", result[1])
+	}
+
+}
+
+func TestGetTransitGatewayVPNConnectionsError(t *testing.T) {
+	mock := &mockVPNConnectionsClient{err: &smithy.GenericAPIError{Code: "UnauthorizedOperation"}}
+
+	_, err := getTransitGatewayVPNConnections(mock)
+	if !IsAccessDeniedError(err) {
+		t.Errorf("expected an access denied error, got %v", err)
+	}
+}
+
+func TestGetDirectConnectGatewayAssociationsError(t *testing.T) {
+	mock := &mockDirectConnectClient{
+		associations: []dxtypes.DirectConnectGatewayAssociation{
+			{AssociationId: aws.String("assoc-1"), DirectConnectGatewayId: aws.String("dxgw-1"), AssociationState: dxtypes.DirectConnectGatewayAssociationStateAssociated},
+		},
+		gatewayErr: &smithy.GenericAPIError{Code: "AccessDeniedException"},
+	}
+
+	result, err := GetDirectConnectGatewayAssociations("tgw-1", mock)
+	if !IsAccessDeniedError(err) {
+		t.Errorf("expected an access denied error, got %v", err)
+	}
+	if result != nil {
+		t.Errorf("expected no associations, got %v", result)
+	}
+}
+
+func TestHybridConnectivitySummaries(t *testing.T) {
+	hybrid := HybridConnectivity{
+		VPNConnections: []VPNConnection{
+			{ID: "vpn-1", TransitGatewayID: "tgw-1", StaticRoutesOnly: true},
+			{ID: "vpn-2", TransitGatewayID: "tgw-2", StaticRoutesOnly: true},
+		},
+		DirectConnectAssociations: []DirectConnectGatewayAssociation{
+			{DirectConnectGatewayID: "dxgw-1", TransitGatewayID: "tgw-1", State: "associated", AllowedPrefixes: []string{"10.0.0.0/8"}},
+			{DirectConnectGatewayID: "dxgw-1", TransitGatewayID: "tgw-2", State: "updating", AllowedPrefixes: []string{"172.16.0.0/12"}},
+		},
+	}
+
+	tests := map[string]map[string]string{
+		"tgw-1": {
+			"vpn-1":  "0/0 tunnels up, Static, 0 accepted routes",
+			"dxgw-1": "associated, allowed prefixes: 10.0.0.0/8",
+		},
+		"tgw-2": {
+			"vpn-2":  "0/0 tunnels up, Static, 0 accepted routes",
+			"dxgw-1": "updating, allowed prefixes: 172.16.0.0/12",
+		},
+	}
+	for tgwID, want := range tests {
+		if got := hybrid.Summaries(tgwID); !reflect.DeepEqual(got, want) {
+			t.Errorf("Summaries(%s) = %v, want %v", tgwID, got, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+)
+
+// TransitGatewayRouteLookup is the result of looking up the route a Transit
+// Gateway will use for traffic coming from an attachment. Route is nil when
+// no route in the associated route table matches the destination.
+type TransitGatewayRouteLookup struct {
+	TransitGateway   TransitGateway
+	SourceAttachment TransitGatewayAttachment
+	RouteTable       TransitGatewayRouteTable
+	Destination      string
+	Route            *TransitGatewayRoute
+}
+
+// IsBlackhole returns whether the matched route drops the traffic
+func (lookup TransitGatewayRouteLookup) IsBlackhole() bool {
+	return lookup.Route != nil && lookup.Route.State == "blackhole"
+}
+
+// HasTarget returns whether the traffic is forwarded to an attachment
+func (lookup TransitGatewayRouteLookup) HasTarget() bool {
+	return lookup.Route != nil && !lookup.IsBlackhole() && lookup.Route.Attachment.ID != ""
+}
+
+// FindTransitGatewayAttachment returns the Transit Gateway and the route table
+// the provided attachment is associated with. The attachment can be provided
+// either by its attachment ID or by the ID of the attached resource (e.g. the
+// VPC ID).
+func FindTransitGatewayAttachment(gateways []TransitGateway, attachment string) (TransitGateway, TransitGatewayRouteTable, TransitGatewayAttachment, error) {
+	for _, gateway := range gateways {
+		for _, routetable := range gateway.RouteTables {
+			for _, source := range routetable.SourceAttachments {
+				if source.ID == attachment || source.ResourceID == attachment {
+					return gateway, routetable, source, nil
+				}
+			}
+		}
+	}
+	return TransitGateway{}, TransitGatewayRouteTable{}, TransitGatewayAttachment{}, fmt.Errorf("attachment %s is not associated with a route table in any of the Transit Gateways", attachment)
+}
+
+// LookupTransitGatewayRoute simulates how the Transit Gateway routes traffic
+// from the provided attachment to the destination IP address. It finds the
+// route table associated with the attachment and performs a longest prefix
+// match across its static, propagated, and blackhole routes.
+func LookupTransitGatewayRoute(gateways []TransitGateway, attachment string, destination string) (TransitGatewayRouteLookup, error) {
+	ip := net.ParseIP(destination)
+	if ip == nil {
+		return TransitGatewayRouteLookup{}, fmt.Errorf("invalid destination IP address: %s", destination)
+	}
+	gateway, routetable, source, err := FindTransitGatewayAttachment(gateways, attachment)
+	if err != nil {
+		return TransitGatewayRouteLookup{}, err
+	}
+	return TransitGatewayRouteLookup{
+		TransitGateway:   gateway,
+		SourceAttachment: source,
+		RouteTable:       routetable,
+		Destination:      destination,
+		Route:            longestPrefixMatch(routetable.Routes, ip),
+	}, nil
+}
+
+// longestPrefixMatch returns the most specific route that contains the IP
+// address. When a static and a propagated route have the same prefix, the
+// static route wins, mirroring the Transit Gateway route evaluation order.
+// Prefix list routes are ignored as their CIDRs aren't known here.
+func longestPrefixMatch(routes []TransitGatewayRoute, ip net.IP) *TransitGatewayRoute {
+	var match *TransitGatewayRoute
+	matchLength := -1
+	for i := range routes {
+		route := &routes[i]
+		_, cidr, err := net.ParseCIDR(route.CIDR)
+		if err != nil || !cidr.Contains(ip) {
+			continue
+		}
+		length, _ := cidr.Mask.Size()
+		if length > matchLength || (length == matchLength && route.RouteType == "static" && match.RouteType != "static") {
+			match = route
+			matchLength = length
+		}
+	}
+	return match
+}
+
+// RoutesToAttachment returns the routes in a route table that forward traffic
+// to the provided attachment
+func (routetable TransitGatewayRouteTable) RoutesToAttachment(attachmentID string) []TransitGatewayRoute {
+	var result []TransitGatewayRoute
+	for _, route := range routetable.Routes {
+		if route.State != "blackhole" && route.Attachment.ID == attachmentID {
+			result = append(result, route)
+		}
+	}
+	return result
+}
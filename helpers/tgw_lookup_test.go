@@ -0,0 +1,122 @@
+package helpers
+
+import (
+	"net"
+	"testing"
+)
+
+func lookupTestGateways() []TransitGateway {
+	vpcA := TransitGatewayAttachment{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}
+	vpcB := TransitGatewayAttachment{ID: "tgw-attach-b", ResourceType: "vpc", ResourceID: "vpc-b"}
+	vpn := TransitGatewayAttachment{ID: "tgw-attach-vpn", ResourceType: "vpn", ResourceID: "vpn-1"}
+	return []TransitGateway{
+		{
+			ID: "tgw-1",
+			RouteTables: map[string]TransitGatewayRouteTable{
+				"tgw-rtb-spoke": {
+					ID:                "tgw-rtb-spoke",
+					SourceAttachments: []TransitGatewayAttachment{vpcA},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "0.0.0.0/0", State: "active", RouteType: "static", Attachment: vpn},
+						{CIDR: "10.1.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcB},
+						{CIDR: "10.1.5.0/24", State: "blackhole", RouteType: "static"},
+						{CIDR: "10.2.0.0/16", State: "active", RouteType: "propagated", Attachment: vpn},
+						{CIDR: "10.2.0.0/16", State: "active", RouteType: "static", Attachment: vpcB},
+						{CIDR: "pl-123456", State: "active", RouteType: "static", Attachment: vpcB},
+					},
+				},
+				"tgw-rtb-shared": {
+					ID:                "tgw-rtb-shared",
+					SourceAttachments: []TransitGatewayAttachment{vpcB},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "10.0.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcA},
+						{CIDR: "10.0.9.0/24", State: "active", RouteType: "static", Attachment: vpn},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestLookupTransitGatewayRoute(t *testing.T) {
+	tests := []struct {
+		name           string
+		attachment     string
+		destination    string
+		wantRouteTable string
+		wantCIDR       string
+		wantTarget     string
+		wantBlackhole  bool
+	}{
+		{"Specific propagated route", "tgw-attach-a", "10.1.2.3", "tgw-rtb-spoke", "10.1.0.0/16", "tgw-attach-b", false},
+		{"Longer blackhole prefix wins", "tgw-attach-a", "10.1.5.10", "tgw-rtb-spoke", "10.1.5.0/24", "", true},
+		{"Static wins over propagated with same prefix", "tgw-attach-a", "10.2.0.1", "tgw-rtb-spoke", "10.2.0.0/16", "tgw-attach-b", false},
+		{"Default route", "tgw-attach-a", "192.168.1.1", "tgw-rtb-spoke", "0.0.0.0/0", "tgw-attach-vpn", false},
+		{"Lookup by resource ID", "vpc-b", "10.0.1.1", "tgw-rtb-shared", "10.0.0.0/16", "tgw-attach-a", false},
+		{"No matching route", "vpc-b", "172.16.0.1", "tgw-rtb-shared", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LookupTransitGatewayRoute(lookupTestGateways(), tt.attachment, tt.destination)
+			if err != nil {
+				t.Fatalf("LookupTransitGatewayRoute() unexpected error: %v", err)
+			}
+			if got.RouteTable.ID != tt.wantRouteTable {
+				t.Errorf("RouteTable = %s, want %s", got.RouteTable.ID, tt.wantRouteTable)
+			}
+			if tt.wantCIDR == "" {
+				if got.Route != nil {
+					t.Errorf("Route = %+v, want nil", got.Route)
+				}
+				return
+			}
+			if got.Route == nil {
+				t.Fatalf("Route = nil, want %s", tt.wantCIDR)
+			}
+			if got.Route.CIDR != tt.wantCIDR {
+				t.Errorf("CIDR = %s, want %s", got.Route.CIDR, tt.wantCIDR)
+			}
+			if got.Route.Attachment.ID != tt.wantTarget {
+				t.Errorf("Target = %s, want %s", got.Route.Attachment.ID, tt.wantTarget)
+			}
+			if got.IsBlackhole() != tt.wantBlackhole {
+				t.Errorf("IsBlackhole() = %v, want %v", got.IsBlackhole(), tt.wantBlackhole)
+			}
+			if got.HasTarget() == tt.wantBlackhole {
+				t.Errorf("HasTarget() = %v, want %v", got.HasTarget(), !tt.wantBlackhole)
+			}
+		})
+	}
+}
+
+func TestLookupTransitGatewayRoute_Errors(t *testing.T) {
+	if _, err := LookupTransitGatewayRoute(lookupTestGateways(), "tgw-attach-a", "not-an-ip"); err == nil {
+		t.Error("expected an error for an invalid destination")
+	}
+	if _, err := LookupTransitGatewayRoute(lookupTestGateways(), "tgw-attach-unknown", "10.0.0.1"); err == nil {
+		t.Error("expected an error for an unassociated attachment")
+	}
+}
+
+func TestLongestPrefixMatch_IPv6(t *testing.T) {
+	routes := []TransitGatewayRoute{
+		{CIDR: "10.0.0.0/8", State: "active"},
+		{CIDR: "2001:db8::/32", State: "active"},
+		{CIDR: "2001:db8:1::/48", State: "active"},
+	}
+	got := longestPrefixMatch(routes, net.ParseIP("2001:db8:1::1"))
+	if got == nil || got.CIDR != "2001:db8:1::/48" {
+		t.Errorf("longestPrefixMatch() = %+v, want 2001:db8:1::/48", got)
+	}
+}
+
+func TestTransitGatewayRouteTable_RoutesToAttachment(t *testing.T) {
+	routetable := lookupTestGateways()[0].RouteTables["tgw-rtb-spoke"]
+	got := routetable.RoutesToAttachment("tgw-attach-b")
+	if len(got) != 3 {
+		t.Fatalf("RoutesToAttachment() returned %d routes, want 3", len(got))
+	}
+	if got := routetable.RoutesToAttachment("tgw-attach-missing"); len(got) != 0 {
+		t.Errorf("RoutesToAttachment() returned %d routes for an unknown attachment, want 0", len(got))
+	}
+}
@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// TransitGatewayAttachmentMatrixEntry describes how a single attachment is
+// connected to the route tables of its Transit Gateway. AssociatedWith is the
+// ID of the route table the attachment is associated with (empty if it isn't
+// associated) and PropagatesTo holds the IDs of the route tables the
+// attachment propagates its routes into.
+type TransitGatewayAttachmentMatrixEntry struct {
+	Attachment     TransitGatewayAttachment
+	AssociatedWith string
+	PropagatesTo   []string
+}
+
+// IsAssociatedWith returns whether the attachment is associated with the route table
+func (entry TransitGatewayAttachmentMatrixEntry) IsAssociatedWith(routetableID string) bool {
+	return entry.AssociatedWith == routetableID
+}
+
+// PropagatesInto returns whether the attachment propagates into the route table
+func (entry TransitGatewayAttachmentMatrixEntry) PropagatesInto(routetableID string) bool {
+	return slices.Contains(entry.PropagatesTo, routetableID)
+}
+
+// RouteTableIDs returns the IDs of the Transit Gateway's route tables in a
+// stable order
+func (gateway TransitGateway) RouteTableIDs() []string {
+	result := make([]string, 0, len(gateway.RouteTables))
+	for id := range gateway.RouteTables {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Attachments returns the attachments of the Transit Gateway from the
+// ownership details of all attachments in the account. Attachments that were
+// removed, rejected, or failed are skipped.
+func (gateway TransitGateway) Attachments(ownership map[string]TransitGatewayAttachmentOwnership) []TransitGatewayAttachment {
+	var result []TransitGatewayAttachment
+	for _, attachment := range ownership {
+		if attachment.TransitGatewayID != gateway.ID {
+			continue
+		}
+		switch types.TransitGatewayAttachmentState(attachment.State) {
+		case types.TransitGatewayAttachmentStateDeleted, types.TransitGatewayAttachmentStateDeleting,
+			types.TransitGatewayAttachmentStateRejected, types.TransitGatewayAttachmentStateFailed:
+			continue
+		}
+		result = append(result, TransitGatewayAttachment{
+			ID:           attachment.ID,
+			ResourceType: attachment.ResourceType,
+			ResourceID:   attachment.ResourceID,
+		})
+	}
+	return result
+}
+
+// AttachmentMatrix returns the association and propagation details of the
+// provided attachments and of every attachment seen in the Transit Gateway's
+// route tables, sorted by attachment ID. Attachments that are neither
+// associated with nor propagating into any route table are included without
+// an association or propagations.
+func (gateway TransitGateway) AttachmentMatrix(attachments []TransitGatewayAttachment) []TransitGatewayAttachmentMatrixEntry {
+	entries := make(map[string]*TransitGatewayAttachmentMatrixEntry)
+	entry := func(attachment TransitGatewayAttachment) *TransitGatewayAttachmentMatrixEntry {
+		if _, ok := entries[attachment.ID]; !ok {
+			entries[attachment.ID] = &TransitGatewayAttachmentMatrixEntry{Attachment: attachment}
+		}
+		return entries[attachment.ID]
+	}
+	for _, attachment := range attachments {
+		entry(attachment)
+	}
+	for _, routetableID := range gateway.RouteTableIDs() {
+		routetable := gateway.RouteTables[routetableID]
+		for _, attachment := range routetable.SourceAttachments {
+			entry(attachment).AssociatedWith = routetableID
+		}
+		for _, attachment := range routetable.DestinationAttachments {
+			matrixEntry := entry(attachment)
+			matrixEntry.PropagatesTo = append(matrixEntry.PropagatesTo, routetableID)
+		}
+	}
+	result := make([]TransitGatewayAttachmentMatrixEntry, 0, len(entries))
+	for _, matrixEntry := range entries {
+		result = append(result, *matrixEntry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Attachment.ID < result[j].Attachment.ID
+	})
+	return result
+}
@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransitGateway_AttachmentMatrix(t *testing.T) {
+	vpcA := TransitGatewayAttachment{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}
+	vpcB := TransitGatewayAttachment{ID: "tgw-attach-b", ResourceType: "vpc", ResourceID: "vpc-b"}
+	vpn := TransitGatewayAttachment{ID: "tgw-attach-c", ResourceType: "vpn", ResourceID: "vpn-1"}
+	isolated := TransitGatewayAttachment{ID: "tgw-attach-d", ResourceType: "vpc", ResourceID: "vpc-d"}
+	gateway := TransitGateway{
+		ID: "tgw-1",
+		RouteTables: map[string]TransitGatewayRouteTable{
+			"tgw-rtb-spoke": {
+				ID:                     "tgw-rtb-spoke",
+				SourceAttachments:      []TransitGatewayAttachment{vpcA, vpcB},
+				DestinationAttachments: []TransitGatewayAttachment{vpn},
+			},
+			"tgw-rtb-shared": {
+				ID:                     "tgw-rtb-shared",
+				DestinationAttachments: []TransitGatewayAttachment{vpcA, vpcB, vpn},
+			},
+		},
+	}
+
+	if got := gateway.RouteTableIDs(); !reflect.DeepEqual(got, []string{"tgw-rtb-shared", "tgw-rtb-spoke"}) {
+		t.Errorf("RouteTableIDs() = %v", got)
+	}
+
+	expected := []TransitGatewayAttachmentMatrixEntry{
+		{Attachment: vpcA, AssociatedWith: "tgw-rtb-spoke", PropagatesTo: []string{"tgw-rtb-shared"}},
+		{Attachment: vpcB, AssociatedWith: "tgw-rtb-spoke", PropagatesTo: []string{"tgw-rtb-shared"}},
+		{Attachment: vpn, PropagatesTo: []string{"tgw-rtb-shared", "tgw-rtb-spoke"}},
+		{Attachment: isolated},
+	}
+	got := gateway.AttachmentMatrix([]TransitGatewayAttachment{isolated, vpcA})
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("AttachmentMatrix() = %+v, want %+v", got, expected)
+	}
+	if !got[0].IsAssociatedWith("tgw-rtb-spoke") || got[0].IsAssociatedWith("tgw-rtb-shared") {
+		t.Error("IsAssociatedWith() returned an unexpected result")
+	}
+	if !got[2].PropagatesInto("tgw-rtb-spoke") || got[0].PropagatesInto("tgw-rtb-spoke") {
+		t.Error("PropagatesInto() returned an unexpected result")
+	}
+}
+
+func TestTransitGateway_Attachments(t *testing.T) {
+	gateway := TransitGateway{ID: "tgw-1"}
+	ownership := map[string]TransitGatewayAttachmentOwnership{
+		"tgw-attach-a": {ID: "tgw-attach-a", TransitGatewayID: "tgw-1", ResourceType: "vpc", ResourceID: "vpc-a", State: "available"},
+		"tgw-attach-b": {ID: "tgw-attach-b", TransitGatewayID: "tgw-1", ResourceType: "vpc", ResourceID: "vpc-b", State: "deleted"},
+		"tgw-attach-c": {ID: "tgw-attach-c", TransitGatewayID: "tgw-2", ResourceType: "vpc", ResourceID: "vpc-c", State: "available"},
+	}
+
+	expected := []TransitGatewayAttachment{{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}}
+	if got := gateway.Attachments(ownership); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Attachments() = %+v, want %+v", got, expected)
+	}
+}
@@ -17,12 +17,14 @@ type mockTGWPaginationClient struct {
 	transitGateways     []types.TransitGateway
 	routeTables         []types.TransitGatewayRouteTable
 	associations        []types.TransitGatewayRouteTableAssociation
+	propagations        []types.TransitGatewayRouteTablePropagation
 	activeRoutes        []types.TransitGatewayRoute
 	blackholeRoutes     []types.TransitGatewayRoute
 	pageSize            int
 	describeTGWCalls    int
 	describeRTCalls     int
 	associationCalls    int
+	propagationCalls    int
 	searchRoutesCalls   int
 	searchRoutesFilters [][]types.Filter
 }
@@ -109,6 +111,33 @@ func (m *mockTGWPaginationClient) GetTransitGatewayRouteTableAssociations(_ cont
 	return out, nil
 }
 
+// GetTransitGatewayRouteTablePropagations paginates through the propagations slice.
+func (m *mockTGWPaginationClient) GetTransitGatewayRouteTablePropagations(_ context.Context, input *ec2.GetTransitGatewayRouteTablePropagationsInput, _ ...func(*ec2.Options)) (*ec2.GetTransitGatewayRouteTablePropagationsOutput, error) {
+	m.propagationCalls++
+	start := 0
+	if input.NextToken != nil {
+		if _, err := fmt.Sscanf(*input.NextToken, "%d", &start); err != nil {
+			return nil, err
+		}
+	}
+	pageSize := m.pageSize
+	if pageSize == 0 {
+		pageSize = len(m.propagations)
+	}
+	end := start + pageSize
+	if end > len(m.propagations) {
+		end = len(m.propagations)
+	}
+	out := &ec2.GetTransitGatewayRouteTablePropagationsOutput{
+		TransitGatewayRouteTablePropagations: m.propagations[start:end],
+	}
+	if end < len(m.propagations) {
+		tok := fmt.Sprintf("%d", end)
+		out.NextToken = &tok
+	}
+	return out, nil
+}
+
 // SearchTransitGatewayRoutes has no NextToken in the AWS API. It returns the
 // filtered slice capped by MaxResults and sets AdditionalRoutesAvailable if
 // there would be more. Active routes are split by type filter (propagated vs
@@ -200,6 +229,20 @@ func makeTransitGatewayAssociations(n int) []types.TransitGatewayRouteTableAssoc
 	return assocs
 }
 
+// makeTransitGatewayPropagations builds n dummy enabled propagations.
+func makeTransitGatewayPropagations(n int) []types.TransitGatewayRouteTablePropagation {
+	propagations := make([]types.TransitGatewayRouteTablePropagation, n)
+	for i := 0; i < n; i++ {
+		propagations[i] = types.TransitGatewayRouteTablePropagation{
+			TransitGatewayAttachmentId: aws.String(fmt.Sprintf("tgw-attach-%08d", i)),
+			ResourceId:                 aws.String(fmt.Sprintf("vpc-%08d", i)),
+			ResourceType:               types.TransitGatewayAttachmentResourceTypeVpc,
+			State:                      types.TransitGatewayPropagationStateEnabled,
+		}
+	}
+	return propagations
+}
+
 // makeTransitGatewayRoutes builds n dummy active TGW routes with a given type
 // so tests can distinguish propagated vs static.
 func makeTransitGatewayRoutes(n int, routeType types.TransitGatewayRouteType) []types.TransitGatewayRoute {
@@ -291,6 +334,34 @@ func TestGetSourceAttachmentsForTransitGatewayRouteTable_Pagination(t *testing.T
 	}
 }
 
+// TestGetDestinationAttachmentsForTransitGatewayRouteTable_Pagination verifies
+// pagination of GetTransitGatewayRouteTablePropagations and that disabled
+// propagations are skipped.
+func TestGetDestinationAttachmentsForTransitGatewayRouteTable_Pagination(t *testing.T) {
+	total := 5
+	propagations := makeTransitGatewayPropagations(total)
+	propagations[4].State = types.TransitGatewayPropagationStateDisabled
+	mock := &mockTGWPaginationClient{
+		propagations: propagations,
+		pageSize:     2,
+	}
+
+	result := getDestinationAttachmentsForTransitGatewayRouteTable("tgw-rtb-00000000", mock)
+
+	if len(result) != total-1 {
+		t.Fatalf("getDestinationAttachmentsForTransitGatewayRouteTable() returned %d attachments, want %d", len(result), total-1)
+	}
+	if mock.propagationCalls != 3 {
+		t.Errorf("GetTransitGatewayRouteTablePropagations called %d times, want 3", mock.propagationCalls)
+	}
+	for i, a := range result {
+		want := fmt.Sprintf("tgw-attach-%08d", i)
+		if a.ID != want {
+			t.Errorf("result[%d].ID = %q, want %q", i, a.ID, want)
+		}
+	}
+}
+
 // TestGetActiveRoutesForTransitGatewayRouteTable_SplitsOnOverflow verifies
 // that when the initial SearchTransitGatewayRoutes response signals more
 // routes are available (hit the 1000 cap), the helper falls back to per-type
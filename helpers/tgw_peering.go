@@ -0,0 +1,197 @@
+package helpers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// TransitGatewayPeer is one side of a Transit Gateway peering attachment
+type TransitGatewayPeer struct {
+	TransitGatewayID string
+	AccountID        string
+	Region           string
+}
+
+// TransitGatewayPeering reflects a Transit Gateway peering attachment
+type TransitGatewayPeering struct {
+	AttachmentID string
+	State        string
+	Requester    TransitGatewayPeer
+	Accepter     TransitGatewayPeer
+}
+
+// PeerOf returns the other side of the peering for the provided Transit Gateway
+func (peering TransitGatewayPeering) PeerOf(tgwID string) TransitGatewayPeer {
+	if peering.Requester.TransitGatewayID == tgwID {
+		return peering.Accepter
+	}
+	return peering.Requester
+}
+
+// Involves returns whether the provided Transit Gateway is one of the sides of the peering
+func (peering TransitGatewayPeering) Involves(tgwID string) bool {
+	return peering.Requester.TransitGatewayID == tgwID || peering.Accepter.TransitGatewayID == tgwID
+}
+
+// TransitGatewayMesh is the collection of Transit Gateways that are connected
+// through peering attachments, potentially spanning multiple regions
+type TransitGatewayMesh struct {
+	TransitGateways []TransitGateway
+	Peerings        []TransitGatewayPeering
+}
+
+// GetTransitGateway returns the Transit Gateway with the provided ID. The
+// boolean is false if the Transit Gateway isn't part of the mesh, which is
+// the case for peers in other accounts.
+func (mesh TransitGatewayMesh) GetTransitGateway(tgwID string) (TransitGateway, bool) {
+	for _, gateway := range mesh.TransitGateways {
+		if gateway.ID == tgwID {
+			return gateway, true
+		}
+	}
+	return TransitGateway{}, false
+}
+
+// PeeringsFor returns the peerings the provided Transit Gateway is part of
+func (mesh TransitGatewayMesh) PeeringsFor(tgwID string) []TransitGatewayPeering {
+	var result []TransitGatewayPeering
+	for _, peering := range mesh.Peerings {
+		if peering.Involves(tgwID) {
+			result = append(result, peering)
+		}
+	}
+	return result
+}
+
+// StaticRoutesOverPeering returns the static routes in any of the Transit
+// Gateway's route tables that send traffic over the peering attachment. As
+// routes aren't propagated over peering attachments, these are the only
+// routes that can use them.
+func (gateway TransitGateway) StaticRoutesOverPeering(attachmentID string) []TransitGatewayRoute {
+	var result []TransitGatewayRoute
+	for _, routetableID := range gateway.RouteTableIDs() {
+		for _, route := range gateway.RouteTables[routetableID].Routes {
+			if route.RouteType == "static" && route.Attachment.ID == attachmentID {
+				result = append(result, route)
+			}
+		}
+	}
+	return result
+}
+
+// tgwMeshAPIClient is the EC2 API surface needed to build a Transit Gateway
+// mesh for a single region
+type tgwMeshAPIClient interface {
+	tgwInventoryAPIClient
+	ec2.DescribeTransitGatewayPeeringAttachmentsAPIClient
+}
+
+// GetTransitGatewayPeerings returns all the Transit Gateway peering attachments in the region, except deleted ones
+func GetTransitGatewayPeerings(svc *ec2.Client) []TransitGatewayPeering {
+	return getTransitGatewayPeerings(svc)
+}
+
+// getTransitGatewayPeerings implements GetTransitGatewayPeerings against the
+// narrow DescribeTransitGatewayPeeringAttachmentsAPIClient interface. It walks
+// NewDescribeTransitGatewayPeeringAttachmentsPaginator.
+func getTransitGatewayPeerings(svc ec2.DescribeTransitGatewayPeeringAttachmentsAPIClient) []TransitGatewayPeering {
+	var result []TransitGatewayPeering
+	paginator := ec2.NewDescribeTransitGatewayPeeringAttachmentsPaginator(svc, &ec2.DescribeTransitGatewayPeeringAttachmentsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		if err != nil {
+			panic(err)
+		}
+		for _, attachment := range page.TransitGatewayPeeringAttachments {
+			if attachment.State == types.TransitGatewayAttachmentStateDeleted {
+				continue
+			}
+			result = append(result, TransitGatewayPeering{
+				AttachmentID: aws.ToString(attachment.TransitGatewayAttachmentId),
+				State:        string(attachment.State),
+				Requester:    transitGatewayPeerFromInfo(attachment.RequesterTgwInfo),
+				Accepter:     transitGatewayPeerFromInfo(attachment.AccepterTgwInfo),
+			})
+		}
+	}
+	return result
+}
+
+func transitGatewayPeerFromInfo(info *types.PeeringTgwInfo) TransitGatewayPeer {
+	if info == nil {
+		return TransitGatewayPeer{}
+	}
+	return TransitGatewayPeer{
+		TransitGatewayID: aws.ToString(info.TransitGatewayId),
+		AccountID:        aws.ToString(info.OwnerId),
+		Region:           aws.ToString(info.Region),
+	}
+}
+
+// GetTransitGatewayMesh collects the Transit Gateways in the starting region
+// and follows their peering attachments into other regions. Peers in the same
+// account are followed recursively, so the full inter-region mesh is
+// returned. Peers owned by other accounts can't be inspected with the current
+// credentials and are only known through the peering attachment.
+func GetTransitGatewayMesh(region string, clientForRegion func(region string) *ec2.Client) TransitGatewayMesh {
+	return getTransitGatewayMesh(region, func(region string) tgwMeshAPIClient {
+		return clientForRegion(region)
+	})
+}
+
+func getTransitGatewayMesh(region string, clientForRegion func(region string) tgwMeshAPIClient) TransitGatewayMesh {
+	mesh := TransitGatewayMesh{}
+	seenPeerings := make(map[string]bool)
+	visited := map[string]bool{region: true}
+	queue := []string{region}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		svc := clientForRegion(current)
+		gateways := getAllTransitGateways(svc)
+		owned := make(map[string]bool)
+		for i := range gateways {
+			if gateways[i].Region == "" {
+				gateways[i].Region = current
+			}
+			owned[gateways[i].ID] = true
+		}
+		mesh.TransitGateways = append(mesh.TransitGateways, gateways...)
+		for _, peering := range getTransitGatewayPeerings(svc) {
+			if seenPeerings[peering.AttachmentID] {
+				continue
+			}
+			seenPeerings[peering.AttachmentID] = true
+			mesh.Peerings = append(mesh.Peerings, peering)
+			for _, side := range []TransitGatewayPeer{peering.Requester, peering.Accepter} {
+				if owned[side.TransitGatewayID] {
+					continue
+				}
+				local := peering.PeerOf(side.TransitGatewayID)
+				if side.AccountID == local.AccountID && side.Region != "" && !visited[side.Region] {
+					visited[side.Region] = true
+					queue = append(queue, side.Region)
+				}
+			}
+		}
+	}
+	sort.Slice(mesh.Peerings, func(i, j int) bool {
+		return mesh.Peerings[i].AttachmentID < mesh.Peerings[j].AttachmentID
+	})
+	return mesh
+}
+
+// regionFromArn returns the region part of an ARN, or an empty string if it
+// isn't a valid ARN
+func regionFromArn(resourceArn string) string {
+	parsed, err := arn.Parse(resourceArn)
+	if err != nil {
+		return ""
+	}
+	return parsed.Region
+}
@@ -0,0 +1,186 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// mockTGWMeshClient extends the TGW pagination mock with peering attachments
+type mockTGWMeshClient struct {
+	mockTGWPaginationClient
+	peerings     []types.TransitGatewayPeeringAttachment
+	peeringCalls int
+}
+
+// DescribeTransitGatewayPeeringAttachments paginates through the peerings slice.
+func (m *mockTGWMeshClient) DescribeTransitGatewayPeeringAttachments(_ context.Context, input *ec2.DescribeTransitGatewayPeeringAttachmentsInput, _ ...func(*ec2.Options)) (*ec2.DescribeTransitGatewayPeeringAttachmentsOutput, error) {
+	m.peeringCalls++
+	start := 0
+	if input.NextToken != nil {
+		if _, err := fmt.Sscanf(*input.NextToken, "%d", &start); err != nil {
+			return nil, err
+		}
+	}
+	pageSize := m.pageSize
+	if pageSize == 0 {
+		pageSize = len(m.peerings)
+	}
+	end := start + pageSize
+	if end > len(m.peerings) {
+		end = len(m.peerings)
+	}
+	out := &ec2.DescribeTransitGatewayPeeringAttachmentsOutput{
+		TransitGatewayPeeringAttachments: m.peerings[start:end],
+	}
+	if end < len(m.peerings) {
+		tok := fmt.Sprintf("%d", end)
+		out.NextToken = &tok
+	}
+	return out, nil
+}
+
+func makeTransitGatewayPeering(id string, requester TransitGatewayPeer, accepter TransitGatewayPeer, state types.TransitGatewayAttachmentState) types.TransitGatewayPeeringAttachment {
+	return types.TransitGatewayPeeringAttachment{
+		TransitGatewayAttachmentId: aws.String(id),
+		State:                      state,
+		RequesterTgwInfo: &types.PeeringTgwInfo{
+			TransitGatewayId: aws.String(requester.TransitGatewayID),
+			OwnerId:          aws.String(requester.AccountID),
+			Region:           aws.String(requester.Region),
+		},
+		AccepterTgwInfo: &types.PeeringTgwInfo{
+			TransitGatewayId: aws.String(accepter.TransitGatewayID),
+			OwnerId:          aws.String(accepter.AccountID),
+			Region:           aws.String(accepter.Region),
+		},
+	}
+}
+
+func makeRegionalTransitGateway(id string, account string, region string) types.TransitGateway {
+	return types.TransitGateway{
+		TransitGatewayId:  aws.String(id),
+		OwnerId:           aws.String(account),
+		TransitGatewayArn: aws.String(fmt.Sprintf("arn:aws:ec2:%s:%s:transit-gateway/%s", region, account, id)),
+	}
+}
+
+func TestGetTransitGatewayPeerings_Pagination(t *testing.T) {
+	sydney := TransitGatewayPeer{TransitGatewayID: "tgw-syd", AccountID: "111111111111", Region: "ap-southeast-2"}
+	tokyo := TransitGatewayPeer{TransitGatewayID: "tgw-tyo", AccountID: "111111111111", Region: "ap-northeast-1"}
+	mock := &mockTGWMeshClient{
+		peerings: []types.TransitGatewayPeeringAttachment{
+			makeTransitGatewayPeering("tgw-attach-1", sydney, tokyo, types.TransitGatewayAttachmentStateAvailable),
+			makeTransitGatewayPeering("tgw-attach-2", sydney, tokyo, types.TransitGatewayAttachmentStateDeleted),
+			makeTransitGatewayPeering("tgw-attach-3", tokyo, sydney, types.TransitGatewayAttachmentStatePendingAcceptance),
+		},
+	}
+	mock.pageSize = 1
+
+	result := getTransitGatewayPeerings(mock)
+
+	if len(result) != 2 {
+		t.Fatalf("getTransitGatewayPeerings() returned %d peerings, want 2 (deleted peerings are skipped)", len(result))
+	}
+	if mock.peeringCalls != 3 {
+		t.Errorf("DescribeTransitGatewayPeeringAttachments called %d times, want 3", mock.peeringCalls)
+	}
+	if result[1].State != "pendingAcceptance" || result[1].Requester != tokyo || result[1].Accepter != sydney {
+		t.Errorf("unexpected peering %+v", result[1])
+	}
+	if result[0].PeerOf("tgw-syd") != tokyo || result[0].PeerOf("tgw-tyo") != sydney {
+		t.Error("PeerOf() returned the wrong side of the peering")
+	}
+}
+
+func TestGetTransitGatewayMesh_FollowsPeerings(t *testing.T) {
+	sydney := TransitGatewayPeer{TransitGatewayID: "tgw-syd", AccountID: "111111111111", Region: "ap-southeast-2"}
+	tokyo := TransitGatewayPeer{TransitGatewayID: "tgw-tyo", AccountID: "111111111111", Region: "ap-northeast-1"}
+	frankfurt := TransitGatewayPeer{TransitGatewayID: "tgw-fra", AccountID: "111111111111", Region: "eu-central-1"}
+	partner := TransitGatewayPeer{TransitGatewayID: "tgw-partner", AccountID: "222222222222", Region: "us-east-1"}
+	sydToTyo := makeTransitGatewayPeering("tgw-attach-syd-tyo", sydney, tokyo, types.TransitGatewayAttachmentStateAvailable)
+	tyoToFra := makeTransitGatewayPeering("tgw-attach-tyo-fra", tokyo, frankfurt, types.TransitGatewayAttachmentStateAvailable)
+	sydToPartner := makeTransitGatewayPeering("tgw-attach-syd-partner", sydney, partner, types.TransitGatewayAttachmentStateAvailable)
+
+	clients := map[string]*mockTGWMeshClient{
+		"ap-southeast-2": {
+			mockTGWPaginationClient: mockTGWPaginationClient{transitGateways: []types.TransitGateway{makeRegionalTransitGateway("tgw-syd", "111111111111", "ap-southeast-2")}},
+			peerings:                []types.TransitGatewayPeeringAttachment{sydToTyo, sydToPartner},
+		},
+		"ap-northeast-1": {
+			mockTGWPaginationClient: mockTGWPaginationClient{transitGateways: []types.TransitGateway{makeRegionalTransitGateway("tgw-tyo", "111111111111", "ap-northeast-1")}},
+			peerings:                []types.TransitGatewayPeeringAttachment{sydToTyo, tyoToFra},
+		},
+		"eu-central-1": {
+			mockTGWPaginationClient: mockTGWPaginationClient{transitGateways: []types.TransitGateway{makeRegionalTransitGateway("tgw-fra", "111111111111", "eu-central-1")}},
+			peerings:                []types.TransitGatewayPeeringAttachment{tyoToFra},
+		},
+	}
+	var visited []string
+	mesh := getTransitGatewayMesh("ap-southeast-2", func(region string) tgwMeshAPIClient {
+		visited = append(visited, region)
+		client, ok := clients[region]
+		if !ok {
+			t.Fatalf("unexpected lookup in region %s", region)
+		}
+		return client
+	})
+
+	if len(visited) != 3 {
+		t.Errorf("visited regions %v, want 3 regions", visited)
+	}
+	if len(mesh.TransitGateways) != 3 {
+		t.Fatalf("mesh contains %d Transit Gateways, want 3", len(mesh.TransitGateways))
+	}
+	if len(mesh.Peerings) != 3 {
+		t.Fatalf("mesh contains %d peerings, want 3 (peerings seen from both sides are deduplicated)", len(mesh.Peerings))
+	}
+	gateway, ok := mesh.GetTransitGateway("tgw-fra")
+	if !ok || gateway.Region != "eu-central-1" {
+		t.Errorf("GetTransitGateway(tgw-fra) = %+v, %v", gateway, ok)
+	}
+	if _, ok := mesh.GetTransitGateway("tgw-partner"); ok {
+		t.Error("Transit Gateway in another account should not be part of the mesh")
+	}
+	if got := len(mesh.PeeringsFor("tgw-tyo")); got != 2 {
+		t.Errorf("PeeringsFor(tgw-tyo) returned %d peerings, want 2", got)
+	}
+}
+
+func TestTransitGateway_StaticRoutesOverPeering(t *testing.T) {
+	peering := TransitGatewayAttachment{ID: "tgw-attach-peer", ResourceType: "peering", ResourceID: "tgw-remote"}
+	gateway := TransitGateway{
+		RouteTables: map[string]TransitGatewayRouteTable{
+			"tgw-rtb-1": {
+				ID: "tgw-rtb-1",
+				Routes: []TransitGatewayRoute{
+					{CIDR: "10.10.0.0/16", State: "active", RouteType: "static", Attachment: peering},
+					{CIDR: "10.0.0.0/16", State: "active", RouteType: "propagated", Attachment: TransitGatewayAttachment{ID: "tgw-attach-vpc"}},
+				},
+			},
+			"tgw-rtb-2": {
+				ID: "tgw-rtb-2",
+				Routes: []TransitGatewayRoute{
+					{CIDR: "10.20.0.0/16", State: "active", RouteType: "static", Attachment: peering},
+				},
+			},
+		},
+	}
+	got := gateway.StaticRoutesOverPeering("tgw-attach-peer")
+	if len(got) != 2 || got[0].CIDR != "10.10.0.0/16" || got[1].CIDR != "10.20.0.0/16" {
+		t.Errorf("StaticRoutesOverPeering() = %+v", got)
+	}
+}
+
+func TestRegionFromArn(t *testing.T) {
+	if got := regionFromArn("arn:aws:ec2:eu-west-1:123456789012:transit-gateway/tgw-1"); got != "eu-west-1" {
+		t.Errorf("regionFromArn() = %q, want eu-west-1", got)
+	}
+	if got := regionFromArn("not-an-arn"); got != "" {
+		t.Errorf("regionFromArn() = %q, want empty string", got)
+	}
+}
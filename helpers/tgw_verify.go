@@ -0,0 +1,204 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Drift types reported when comparing Transit Gateway route tables to a spec
+const (
+	TransitGatewayDriftRouteTable  = "Route Table"
+	TransitGatewayDriftAssociation = "Association"
+	TransitGatewayDriftPropagation = "Propagation"
+	TransitGatewayDriftStaticRoute = "Static Route"
+)
+
+// Drift changes, describing what needs to happen to the live configuration
+// to match the spec
+const (
+	TransitGatewayDriftAdd    = "add"
+	TransitGatewayDriftRemove = "remove"
+)
+
+// TransitGatewaySpec is the desired state of Transit Gateway route tables
+type TransitGatewaySpec struct {
+	RouteTables []TransitGatewayRouteTableSpec `yaml:"route_tables"`
+}
+
+// TransitGatewayRouteTableSpec is the desired state of a single Transit
+// Gateway route table. Attachments can be referred to by either their
+// attachment ID or the ID of the attached resource (e.g. the VPC ID). Only
+// the static routes are verified, as propagated routes follow from the
+// propagations.
+type TransitGatewayRouteTableSpec struct {
+	ID           string                          `yaml:"id"`
+	Name         string                          `yaml:"name"`
+	Associations []string                        `yaml:"associations"`
+	Propagations []string                        `yaml:"propagations"`
+	StaticRoutes []TransitGatewayStaticRouteSpec `yaml:"static_routes"`
+}
+
+// TransitGatewayStaticRouteSpec is an expected static route. A route either
+// has an attachment or is a blackhole route.
+type TransitGatewayStaticRouteSpec struct {
+	CIDR       string `yaml:"cidr"`
+	Attachment string `yaml:"attachment"`
+	Blackhole  bool   `yaml:"blackhole"`
+}
+
+// String returns the route in the same format as the live routes in the drift report
+func (route TransitGatewayStaticRouteSpec) String() string {
+	if route.Blackhole {
+		return route.CIDR + " -> blackhole"
+	}
+	return route.CIDR + " -> " + route.Attachment
+}
+
+// TransitGatewayDrift is a single difference between a spec and the live
+// Transit Gateway route table
+type TransitGatewayDrift struct {
+	RouteTable string
+	Type       string
+	Change     string
+	Value      string
+}
+
+// String returns the drift as a diff line, prefixed with + for what needs to
+// be added and - for what needs to be removed
+func (drift TransitGatewayDrift) String() string {
+	prefix := "+"
+	if drift.Change == TransitGatewayDriftRemove {
+		prefix = "-"
+	}
+	return fmt.Sprintf("%s %s %s", prefix, drift.Type, drift.Value)
+}
+
+// LoadTransitGatewaySpec reads and validates a Transit Gateway spec from a YAML file
+func LoadTransitGatewaySpec(path string) (TransitGatewaySpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TransitGatewaySpec{}, fmt.Errorf("failed to read spec file %s: %w", path, err)
+	}
+	return ParseTransitGatewaySpec(data)
+}
+
+// ParseTransitGatewaySpec parses and validates a Transit Gateway spec
+func ParseTransitGatewaySpec(data []byte) (TransitGatewaySpec, error) {
+	var spec TransitGatewaySpec
+	if err := yaml.UnmarshalStrict(data, &spec); err != nil {
+		return TransitGatewaySpec{}, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if len(spec.RouteTables) == 0 {
+		return TransitGatewaySpec{}, fmt.Errorf("spec doesn't contain any route tables")
+	}
+	for _, routetable := range spec.RouteTables {
+		if routetable.ID == "" {
+			return TransitGatewaySpec{}, fmt.Errorf("route table %q in spec is missing an id", routetable.Name)
+		}
+		for _, route := range routetable.StaticRoutes {
+			if route.CIDR == "" {
+				return TransitGatewaySpec{}, fmt.Errorf("static route in route table %s is missing a cidr", routetable.ID)
+			}
+			if route.Blackhole == (route.Attachment != "") {
+				return TransitGatewaySpec{}, fmt.Errorf("static route %s in route table %s needs either an attachment or blackhole set", route.CIDR, routetable.ID)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// VerifyTransitGateways compares the route tables of the Transit Gateways
+// against the spec and returns all differences. Route tables that exist but
+// aren't in the spec are ignored.
+func VerifyTransitGateways(gateways []TransitGateway, spec TransitGatewaySpec) []TransitGatewayDrift {
+	var result []TransitGatewayDrift
+	for _, expected := range spec.RouteTables {
+		live, ok := findTransitGatewayRouteTable(gateways, expected.ID)
+		if !ok {
+			result = append(result, TransitGatewayDrift{RouteTable: expected.ID, Type: TransitGatewayDriftRouteTable, Change: TransitGatewayDriftAdd, Value: expected.ID})
+			continue
+		}
+		result = append(result, verifyAttachments(expected.ID, TransitGatewayDriftAssociation, expected.Associations, live.SourceAttachments)...)
+		result = append(result, verifyAttachments(expected.ID, TransitGatewayDriftPropagation, expected.Propagations, live.DestinationAttachments)...)
+		result = append(result, verifyStaticRoutes(expected.ID, expected.StaticRoutes, live.Routes)...)
+	}
+	return result
+}
+
+func findTransitGatewayRouteTable(gateways []TransitGateway, routetableID string) (TransitGatewayRouteTable, bool) {
+	for _, gateway := range gateways {
+		if routetable, ok := gateway.RouteTables[routetableID]; ok {
+			return routetable, true
+		}
+	}
+	return TransitGatewayRouteTable{}, false
+}
+
+func attachmentMatches(attachment TransitGatewayAttachment, reference string) bool {
+	return reference != "" && (attachment.ID == reference || attachment.ResourceID == reference)
+}
+
+func verifyAttachments(routetableID string, drifttype string, expected []string, live []TransitGatewayAttachment) []TransitGatewayDrift {
+	var result []TransitGatewayDrift
+	for _, reference := range expected {
+		if !slices.ContainsFunc(live, func(attachment TransitGatewayAttachment) bool {
+			return attachmentMatches(attachment, reference)
+		}) {
+			result = append(result, TransitGatewayDrift{RouteTable: routetableID, Type: drifttype, Change: TransitGatewayDriftAdd, Value: reference})
+		}
+	}
+	for _, attachment := range live {
+		if !slices.ContainsFunc(expected, func(reference string) bool {
+			return attachmentMatches(attachment, reference)
+		}) {
+			result = append(result, TransitGatewayDrift{RouteTable: routetableID, Type: drifttype, Change: TransitGatewayDriftRemove, Value: attachment.ID})
+		}
+	}
+	return result
+}
+
+func staticRouteMatches(route TransitGatewayRoute, expected TransitGatewayStaticRouteSpec) bool {
+	if route.CIDR != expected.CIDR {
+		return false
+	}
+	if expected.Blackhole {
+		return route.State == "blackhole"
+	}
+	return route.State != "blackhole" && attachmentMatches(route.Attachment, expected.Attachment)
+}
+
+func liveStaticRouteString(route TransitGatewayRoute) string {
+	if route.State == "blackhole" {
+		return route.CIDR + " -> blackhole"
+	}
+	return route.CIDR + " -> " + route.Attachment.ID
+}
+
+func verifyStaticRoutes(routetableID string, expected []TransitGatewayStaticRouteSpec, routes []TransitGatewayRoute) []TransitGatewayDrift {
+	var live []TransitGatewayRoute
+	for _, route := range routes {
+		// Blackhole routes are always static, even when the SDK doesn't report a type
+		if route.RouteType == "static" || route.State == "blackhole" {
+			live = append(live, route)
+		}
+	}
+	var result []TransitGatewayDrift
+	for _, route := range expected {
+		if !slices.ContainsFunc(live, func(liveroute TransitGatewayRoute) bool {
+			return staticRouteMatches(liveroute, route)
+		}) {
+			result = append(result, TransitGatewayDrift{RouteTable: routetableID, Type: TransitGatewayDriftStaticRoute, Change: TransitGatewayDriftAdd, Value: route.String()})
+		}
+	}
+	for _, liveroute := range live {
+		if !slices.ContainsFunc(expected, func(route TransitGatewayStaticRouteSpec) bool {
+			return staticRouteMatches(liveroute, route)
+		}) {
+			result = append(result, TransitGatewayDrift{RouteTable: routetableID, Type: TransitGatewayDriftStaticRoute, Change: TransitGatewayDriftRemove, Value: liveStaticRouteString(liveroute)})
+		}
+	}
+	return result
+}
@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testTransitGatewaySpec = `
+route_tables:
+  - id: tgw-rtb-spoke
+    name: spoke
+    associations:
+      - vpc-a
+      - tgw-attach-b
+    propagations:
+      - tgw-attach-vpn
+    static_routes:
+      - cidr: 0.0.0.0/0
+        attachment: tgw-attach-vpn
+      - cidr: 10.99.0.0/16
+        blackhole: true
+      - cidr: 10.50.0.0/16
+        attachment: vpc-b
+  - id: tgw-rtb-missing
+`
+
+func TestParseTransitGatewaySpec(t *testing.T) {
+	spec, err := ParseTransitGatewaySpec([]byte(testTransitGatewaySpec))
+	if err != nil {
+		t.Fatalf("ParseTransitGatewaySpec() unexpected error: %v", err)
+	}
+	if len(spec.RouteTables) != 2 {
+		t.Fatalf("got %d route tables, want 2", len(spec.RouteTables))
+	}
+	routetable := spec.RouteTables[0]
+	if routetable.ID != "tgw-rtb-spoke" || routetable.Name != "spoke" {
+		t.Errorf("unexpected route table %+v", routetable)
+	}
+	if !reflect.DeepEqual(routetable.Associations, []string{"vpc-a", "tgw-attach-b"}) {
+		t.Errorf("Associations = %v", routetable.Associations)
+	}
+	if len(routetable.StaticRoutes) != 3 || !routetable.StaticRoutes[1].Blackhole {
+		t.Errorf("StaticRoutes = %+v", routetable.StaticRoutes)
+	}
+}
+
+func TestParseTransitGatewaySpec_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"empty":             ``,
+		"unknown field":     "route_tables:\n  - id: tgw-rtb-1\n    asociations: [vpc-a]\n",
+		"missing id":        "route_tables:\n  - name: spoke\n",
+		"missing cidr":      "route_tables:\n  - id: tgw-rtb-1\n    static_routes:\n      - attachment: vpc-a\n",
+		"missing target":    "route_tables:\n  - id: tgw-rtb-1\n    static_routes:\n      - cidr: 10.0.0.0/8\n",
+		"target and hole":   "route_tables:\n  - id: tgw-rtb-1\n    static_routes:\n      - cidr: 10.0.0.0/8\n        attachment: vpc-a\n        blackhole: true\n",
+		"invalid yaml":      "route_tables: [",
+		"wrong association": "route_tables:\n  - id: tgw-rtb-1\n    associations: vpc-a\n",
+	}
+	for name, spec := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseTransitGatewaySpec([]byte(spec)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLoadTransitGatewaySpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tgw.yaml")
+	if err := os.WriteFile(path, []byte(testTransitGatewaySpec), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTransitGatewaySpec(path); err != nil {
+		t.Errorf("LoadTransitGatewaySpec() unexpected error: %v", err)
+	}
+	if _, err := LoadTransitGatewaySpec(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestVerifyTransitGateways(t *testing.T) {
+	vpcA := TransitGatewayAttachment{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}
+	vpcB := TransitGatewayAttachment{ID: "tgw-attach-b", ResourceType: "vpc", ResourceID: "vpc-b"}
+	vpcC := TransitGatewayAttachment{ID: "tgw-attach-c", ResourceType: "vpc", ResourceID: "vpc-c"}
+	vpn := TransitGatewayAttachment{ID: "tgw-attach-vpn", ResourceType: "vpn", ResourceID: "vpn-1"}
+	gateways := []TransitGateway{
+		{
+			ID: "tgw-1",
+			RouteTables: map[string]TransitGatewayRouteTable{
+				"tgw-rtb-spoke": {
+					ID:                     "tgw-rtb-spoke",
+					SourceAttachments:      []TransitGatewayAttachment{vpcA, vpcC},
+					DestinationAttachments: []TransitGatewayAttachment{vpn, vpcA},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "0.0.0.0/0", State: "active", RouteType: "static", Attachment: vpn},
+						{CIDR: "10.0.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcA},
+						{CIDR: "10.50.0.0/16", State: "blackhole", RouteType: "static"},
+						{CIDR: "10.60.0.0/16", State: "active", RouteType: "static", Attachment: vpcB},
+					},
+				},
+			},
+		},
+	}
+	spec, err := ParseTransitGatewaySpec([]byte(testTransitGatewaySpec))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := VerifyTransitGateways(gateways, spec)
+
+	expected := []string{
+		"+ Association tgw-attach-b",
+		"- Association tgw-attach-c",
+		"- Propagation tgw-attach-a",
+		"+ Static Route 10.99.0.0/16 -> blackhole",
+		"+ Static Route 10.50.0.0/16 -> vpc-b",
+		"- Static Route 10.50.0.0/16 -> blackhole",
+		"- Static Route 10.60.0.0/16 -> tgw-attach-b",
+		"+ Route Table tgw-rtb-missing",
+	}
+	lines := make([]string, 0, len(got))
+	for _, drift := range got {
+		lines = append(lines, drift.String())
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("VerifyTransitGateways() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(expected, "\n"))
+	}
+	if got[0].RouteTable != "tgw-rtb-spoke" || got[len(got)-1].RouteTable != "tgw-rtb-missing" {
+		t.Error("drift is attributed to the wrong route table")
+	}
+}
+
+func TestVerifyTransitGateways_NoDrift(t *testing.T) {
+	vpcA := TransitGatewayAttachment{ID: "tgw-attach-a", ResourceType: "vpc", ResourceID: "vpc-a"}
+	gateways := []TransitGateway{
+		{
+			ID: "tgw-1",
+			RouteTables: map[string]TransitGatewayRouteTable{
+				"tgw-rtb-1": {
+					ID:                     "tgw-rtb-1",
+					SourceAttachments:      []TransitGatewayAttachment{vpcA},
+					DestinationAttachments: []TransitGatewayAttachment{vpcA},
+					Routes: []TransitGatewayRoute{
+						{CIDR: "10.0.0.0/16", State: "active", RouteType: "propagated", Attachment: vpcA},
+					},
+				},
+			},
+		},
+	}
+	spec := TransitGatewaySpec{RouteTables: []TransitGatewayRouteTableSpec{
+		{ID: "tgw-rtb-1", Associations: []string{"vpc-a"}, Propagations: []string{"tgw-attach-a"}},
+	}}
+	if got := VerifyTransitGateways(gateways, spec); len(got) != 0 {
+		t.Errorf("VerifyTransitGateways() = %+v, want no drift", got)
+	}
+}
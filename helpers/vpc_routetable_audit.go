@@ -0,0 +1,167 @@
+package helpers
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// VPC route table audit issue types
+const (
+	RouteAuditIssueBlackhole       = "Blackhole route"
+	RouteAuditIssueDeletedPeering  = "Route to deleted peering connection"
+	RouteAuditIssueDeletedENI      = "Route to deleted network interface"
+	RouteAuditIssueRedundant       = "Redundant route"
+	RouteAuditIssueOverlapping     = "Overlapping route"
+	RouteAuditIssueNoAssociations  = "No associations"
+	RouteAuditIssueImplicitSubnets = "Subnet uses main route table implicitly"
+)
+
+// VPCRouteTableFinding describes a single hygiene issue found in a VPC route
+// table. Destination and Target are only set for findings that relate to a
+// specific route, Subnet only for implicit main route table usage.
+type VPCRouteTableFinding struct {
+	RouteTable  VPCRouteTable
+	Issue       string
+	Destination string
+	Target      string
+	Subnet      string
+	Details     string
+}
+
+// AuditVPCRouteTables inspects the provided route tables for common hygiene
+// problems: blackhole routes (including those whose peering connection or
+// network interface no longer exists), routes that overlap with or are made
+// redundant by a broader route in the same table, route tables that aren't
+// associated with anything, and subnets that silently fall back to the main
+// route table of their VPC.
+func AuditVPCRouteTables(routetables []VPCRouteTable, subnets []types.Subnet) []VPCRouteTableFinding {
+	var result []VPCRouteTableFinding
+	explicitSubnets := make(map[string]bool)
+	mainTables := make(map[string]VPCRouteTable)
+	for _, routetable := range routetables {
+		for _, subnet := range routetable.Subnets {
+			explicitSubnets[subnet] = true
+		}
+		if routetable.Default {
+			mainTables[routetable.Vpc.ID] = routetable
+		}
+	}
+	for _, routetable := range routetables {
+		result = append(result, auditVPCRoutes(routetable)...)
+		if !routetable.Default && len(routetable.Subnets) == 0 && len(routetable.Gateways) == 0 {
+			result = append(result, VPCRouteTableFinding{
+				RouteTable: routetable,
+				Issue:      RouteAuditIssueNoAssociations,
+				Details:    "Route table is not the main route table and has no subnet or gateway associations",
+			})
+		}
+	}
+	for _, subnet := range subnets {
+		subnetID := aws.ToString(subnet.SubnetId)
+		if explicitSubnets[subnetID] {
+			continue
+		}
+		maintable, ok := mainTables[aws.ToString(subnet.VpcId)]
+		if !ok {
+			continue
+		}
+		result = append(result, VPCRouteTableFinding{
+			RouteTable: maintable,
+			Issue:      RouteAuditIssueImplicitSubnets,
+			Subnet:     subnetID,
+			Details:    "Subnet has no explicit route table association and uses the main route table",
+		})
+	}
+	return result
+}
+
+// auditVPCRoutes checks the individual routes of a single route table
+func auditVPCRoutes(routetable VPCRouteTable) []VPCRouteTableFinding {
+	var result []VPCRouteTableFinding
+	for _, route := range routetable.Routes {
+		if route.State != string(types.RouteStateBlackhole) {
+			continue
+		}
+		finding := VPCRouteTableFinding{
+			RouteTable:  routetable,
+			Issue:       RouteAuditIssueBlackhole,
+			Destination: route.DestinationCIDR,
+			Target:      route.DestinationTarget,
+			Details:     "The target of this route is no longer available",
+		}
+		switch TypeByResourceID(route.DestinationTarget) {
+		case "pcx":
+			finding.Issue = RouteAuditIssueDeletedPeering
+			finding.Details = "The VPC peering connection has been deleted or is no longer active"
+		case "eni":
+			finding.Issue = RouteAuditIssueDeletedENI
+			finding.Details = "The network interface has been deleted or its instance is stopped"
+		}
+		result = append(result, finding)
+	}
+	for i, specific := range routetable.Routes {
+		_, specificNet, err := net.ParseCIDR(specific.DestinationCIDR)
+		if err != nil {
+			// Prefix lists can't be compared without resolving them
+			continue
+		}
+		broad, found := closestCoveringRoute(routetable.Routes, i, specificNet)
+		if !found {
+			continue
+		}
+		finding := VPCRouteTableFinding{
+			RouteTable:  routetable,
+			Destination: specific.DestinationCIDR,
+			Target:      specific.DestinationTarget,
+		}
+		if specific.DestinationTarget == broad.DestinationTarget {
+			finding.Issue = RouteAuditIssueRedundant
+			finding.Details = fmt.Sprintf("Already covered by %s to the same target", broad.DestinationCIDR)
+		} else {
+			finding.Issue = RouteAuditIssueOverlapping
+			finding.Details = fmt.Sprintf("Overrides part of %s (%s)", broad.DestinationCIDR, broad.DestinationTarget)
+		}
+		result = append(result, finding)
+	}
+	return result
+}
+
+// closestCoveringRoute returns the route with the most specific prefix that
+// contains the network of the route at index. Default routes and the local
+// route of the VPC are ignored, as more specific routes within them are how
+// route tables are meant to be used.
+func closestCoveringRoute(routes []VPCRoute, index int, network *net.IPNet) (VPCRoute, bool) {
+	var result VPCRoute
+	closest := -1
+	for j, broad := range routes {
+		if j == index || broad.DestinationTarget == "local" {
+			continue
+		}
+		_, broadNet, err := net.ParseCIDR(broad.DestinationCIDR)
+		if err != nil || !cidrContains(broadNet, network) {
+			continue
+		}
+		ones, _ := broadNet.Mask.Size()
+		if ones == 0 || ones <= closest {
+			continue
+		}
+		result = broad
+		closest = ones
+	}
+	return result, closest >= 0
+}
+
+// cidrContains reports whether broad fully contains specific while being a
+// strictly larger network. Networks of a different address family never
+// contain each other.
+func cidrContains(broad, specific *net.IPNet) bool {
+	broadOnes, broadBits := broad.Mask.Size()
+	specificOnes, specificBits := specific.Mask.Size()
+	if broadBits != specificBits || broadOnes >= specificOnes {
+		return false
+	}
+	return broad.Contains(specific.IP)
+}
@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// countFindings returns how many findings of the given issue type are present
+func countFindings(findings []VPCRouteTableFinding, issue string) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.Issue == issue {
+			count++
+		}
+	}
+	return count
+}
+
+func TestAuditVPCRouteTables_Blackholes(t *testing.T) {
+	routetable := VPCRouteTable{
+		Vpc: VPCHolder{ID: "vpc-1"},
+		ID:  "rtb-1",
+		Routes: []VPCRoute{
+			{DestinationCIDR: "10.0.0.0/16", State: "active", DestinationTarget: "local"},
+			{DestinationCIDR: "10.1.0.0/16", State: "blackhole", DestinationTarget: "pcx-1234"},
+			{DestinationCIDR: "10.2.0.0/16", State: "blackhole", DestinationTarget: "eni-1234"},
+			{DestinationCIDR: "10.3.0.0/16", State: "blackhole", DestinationTarget: "tgw-1234"},
+		},
+		Subnets: []string{"subnet-1"},
+	}
+
+	findings := AuditVPCRouteTables([]VPCRouteTable{routetable}, nil)
+
+	if got := countFindings(findings, RouteAuditIssueDeletedPeering); got != 1 {
+		t.Errorf("expected 1 deleted peering finding, got %d", got)
+	}
+	if got := countFindings(findings, RouteAuditIssueDeletedENI); got != 1 {
+		t.Errorf("expected 1 deleted ENI finding, got %d", got)
+	}
+	if got := countFindings(findings, RouteAuditIssueBlackhole); got != 1 {
+		t.Errorf("expected 1 generic blackhole finding, got %d", got)
+	}
+	if len(findings) != 3 {
+		t.Errorf("expected 3 findings in total, got %d: %+v", len(findings), findings)
+	}
+}
+
+func TestAuditVPCRouteTables_OverlappingRoutes(t *testing.T) {
+	routetable := VPCRouteTable{
+		Vpc: VPCHolder{ID: "vpc-1"},
+		ID:  "rtb-1",
+		Routes: []VPCRoute{
+			{DestinationCIDR: "10.0.0.0/8", State: "active", DestinationTarget: "tgw-1"},
+			{DestinationCIDR: "10.20.0.0/16", State: "active", DestinationTarget: "tgw-1"},
+			{DestinationCIDR: "10.30.0.0/16", State: "active", DestinationTarget: "pcx-1"},
+			{DestinationCIDR: "2001:db8::/32", State: "active", DestinationTarget: "eigw-1"},
+			{DestinationCIDR: "pl-12345", State: "active", DestinationTarget: "vpce-1"},
+		},
+		Subnets: []string{"subnet-1"},
+	}
+
+	findings := AuditVPCRouteTables([]VPCRouteTable{routetable}, nil)
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].Issue != RouteAuditIssueRedundant || findings[0].Destination != "10.20.0.0/16" {
+		t.Errorf("expected 10.20.0.0/16 to be redundant, got %+v", findings[0])
+	}
+	if findings[1].Issue != RouteAuditIssueOverlapping || findings[1].Destination != "10.30.0.0/16" {
+		t.Errorf("expected 10.30.0.0/16 to be overlapping, got %+v", findings[1])
+	}
+}
+
+func TestAuditVPCRouteTables_OverlappingRoutesIgnoreDefaultAndLocal(t *testing.T) {
+	routetable := VPCRouteTable{
+		Vpc: VPCHolder{ID: "vpc-1"},
+		ID:  "rtb-1",
+		Routes: []VPCRoute{
+			{DestinationCIDR: "10.0.0.0/16", State: "active", DestinationTarget: "local"},
+			{DestinationCIDR: "10.0.128.0/24", State: "active", DestinationTarget: "eni-1"},
+			{DestinationCIDR: "0.0.0.0/0", State: "active", DestinationTarget: "nat-1"},
+			{DestinationCIDR: "::/0", State: "active", DestinationTarget: "eigw-1"},
+			{DestinationCIDR: "2001:db8::/32", State: "active", DestinationTarget: "tgw-1"},
+			{DestinationCIDR: "172.16.0.0/12", State: "active", DestinationTarget: "tgw-1"},
+			{DestinationCIDR: "172.16.0.0/16", State: "active", DestinationTarget: "pcx-1"},
+			{DestinationCIDR: "172.16.5.0/24", State: "active", DestinationTarget: "pcx-1"},
+		},
+		Subnets: []string{"subnet-1"},
+	}
+
+	findings := AuditVPCRouteTables([]VPCRouteTable{routetable}, nil)
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].Issue != RouteAuditIssueOverlapping || findings[0].Destination != "172.16.0.0/16" {
+		t.Errorf("expected 172.16.0.0/16 to overlap 172.16.0.0/12, got %+v", findings[0])
+	}
+	// Only the closest covering route is reported, which has the same target
+	if findings[1].Issue != RouteAuditIssueRedundant || findings[1].Destination != "172.16.5.0/24" || findings[1].Details != "Already covered by 172.16.0.0/16 to the same target" {
+		t.Errorf("expected 172.16.5.0/24 to be redundant because of 172.16.0.0/16, got %+v", findings[1])
+	}
+}
+
+func TestAuditVPCRouteTables_Associations(t *testing.T) {
+	routetables := []VPCRouteTable{
+		{Vpc: VPCHolder{ID: "vpc-1"}, ID: "rtb-main", Default: true},
+		{Vpc: VPCHolder{ID: "vpc-1"}, ID: "rtb-subnet", Subnets: []string{"subnet-explicit"}},
+		{Vpc: VPCHolder{ID: "vpc-1"}, ID: "rtb-edge", Gateways: []string{"igw-1"}},
+		{Vpc: VPCHolder{ID: "vpc-1"}, ID: "rtb-unused"},
+	}
+	subnets := []types.Subnet{
+		{SubnetId: aws.String("subnet-explicit"), VpcId: aws.String("vpc-1")},
+		{SubnetId: aws.String("subnet-implicit"), VpcId: aws.String("vpc-1")},
+		{SubnetId: aws.String("subnet-othervpc"), VpcId: aws.String("vpc-2")},
+	}
+
+	findings := AuditVPCRouteTables(routetables, subnets)
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+	if findings[0].Issue != RouteAuditIssueNoAssociations || findings[0].RouteTable.ID != "rtb-unused" {
+		t.Errorf("expected rtb-unused to have no associations, got %+v", findings[0])
+	}
+	if findings[1].Issue != RouteAuditIssueImplicitSubnets || findings[1].Subnet != "subnet-implicit" || findings[1].RouteTable.ID != "rtb-main" {
+		t.Errorf("expected subnet-implicit to use rtb-main implicitly, got %+v", findings[1])
+	}
+}
+
+func TestGetAllVPCRouteTables_MainAndGatewayAssociations(t *testing.T) {
+	tables := makeRouteTables(1)
+	tables[0].Associations = []types.RouteTableAssociation{
+		{Main: aws.Bool(true)},
+		{GatewayId: aws.String("igw-1")},
+	}
+	mock := &mockDescribeRouteTablesClient{routeTables: tables}
+
+	result := getAllVPCRouteTables(mock)
+
+	if len(result) != 1 {
+		t.Fatalf("expected 1 route table, got %d", len(result))
+	}
+	if !result[0].Default {
+		t.Error("expected the route table to be marked as the main route table")
+	}
+	if len(result[0].Gateways) != 1 || result[0].Gateways[0] != "igw-1" {
+		t.Errorf("expected gateway association igw-1, got %v", result[0].Gateways)
+	}
+}